@@ -20,6 +20,33 @@ WaitTxToBeMined = "2m"
 ForcedGas = 0
 GasPriceMarginFactor = 1
 MaxGasPriceLimit = 0
+MaxBlobGasPriceLimit = 0
+BlobGasPriceMarginFactor = 1
+TxType = "legacy"
+GasTipCap = 0
+MaxPriorityFeePerGas = 0
+MaxFeePerGas = 0
+BaseFeeMultiplier = 2
+	[EthTxManager.GasPriceOracle]
+		FeeHistoryBlockCount = 20
+		RewardPercentile = 60
+		RepriceBumpPercentage = 15
+	[EthTxManager.RPC]
+		Host = "0.0.0.0"
+		Port = 8645
+		EnableAuth = false
+		AuthToken = ""
+		RateLimitPerSecond = 0
+		EnableGRPC = false
+		GRPCPort = 8546
+	[EthTxManager.Aggregator]
+		FlushInterval = "5s"
+		MaxCallsPerBatch = 50
+		MaxBatchGas = 10000000
+		MulticallAddress = "0xcA11bde05977b3631167028862bE2a173976CA11"
+	[EthTxManager.Dispatch]
+		Strategy = "round-robin"
+		StalenessTimeout = "5m"
 	[State.DB]
 		User = "ethtxmanager_user"
 		Password = "ethtxmanager_password"
@@ -0,0 +1,317 @@
+package ethtxmanager
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/0xPolygonHermez/zkevm-ethtx-manager/config/types"
+	"github.com/0xPolygonHermez/zkevm-ethtx-manager/log"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AggregatorConfig configures the optional Multicall3-based batching
+// subsystem under [EthTxManager.Aggregator].
+type AggregatorConfig struct {
+	// FlushInterval is how often queued calls are flushed into a single
+	// aggregate3 tx, regardless of how many calls are queued.
+	FlushInterval types.Duration `mapstructure:"FlushInterval"`
+	// MaxCallsPerBatch caps how many calls are flushed into a single tx.
+	MaxCallsPerBatch int `mapstructure:"MaxCallsPerBatch"`
+	// MaxBatchGas caps the total gas a batch is allowed to request, calls
+	// that would push the running total over this limit are left queued
+	// for the next flush.
+	MaxBatchGas uint64 `mapstructure:"MaxBatchGas"`
+	// MulticallAddress is the address of the deployed Multicall3-compatible
+	// contract used to aggregate queued calls.
+	MulticallAddress common.Address `mapstructure:"MulticallAddress"`
+}
+
+// CallResult is the outcome of a single call flushed as part of a batch.
+type CallResult struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// batchStorage persists the mapping from a batch tx hash to the IDs of the
+// calls it carries, so a restart can still route results back once the tx
+// is mined.
+type batchStorage interface {
+	StoreBatch(ctx context.Context, txHash common.Hash, callIDs []string) error
+	GetBatch(ctx context.Context, txHash common.Hash) ([]string, error)
+}
+
+// batchSender sends the aggregate3 tx built from a batch of queued calls,
+// satisfied by the ethtxmanager Client's Add method.
+type batchSender interface {
+	Add(ctx context.Context, to *common.Address, value *big.Int, data []byte, blobs ...[]byte) (common.Hash, error)
+}
+
+// queuedCall is a single call waiting to be flushed into a batch.
+type queuedCall struct {
+	id       string
+	to       common.Address
+	data     []byte
+	estGas   uint64
+	resultCh chan CallResult
+}
+
+// Aggregator batches independent L1 calls queued through AddToBatch into a
+// single Multicall3 aggregate3 tx, inspired by the claim-sponsor pattern
+// where many bridge claims are settled by a shared sender.
+type Aggregator struct {
+	cfg     AggregatorConfig
+	sender  batchSender
+	storage interface {
+		batchStorage
+		callResultStorage
+	}
+	abi abi.ABI
+
+	mu     sync.Mutex
+	queue  []*queuedCall
+	nextID uint64
+
+	pendingMu sync.Mutex
+	pending   map[common.Hash][]*queuedCall
+}
+
+// multicall3ABIJSON is the minimal Multicall3 ABI fragment needed to encode
+// aggregate3 calls and decode its Result[] return value.
+const multicall3ABIJSON = `[{
+	"name": "aggregate3",
+	"type": "function",
+	"stateMutability": "payable",
+	"inputs": [{
+		"name": "calls",
+		"type": "tuple[]",
+		"components": [
+			{"name": "target", "type": "address"},
+			{"name": "allowFailure", "type": "bool"},
+			{"name": "callData", "type": "bytes"}
+		]
+	}],
+	"outputs": [{
+		"name": "returnData",
+		"type": "tuple[]",
+		"components": [
+			{"name": "success", "type": "bool"},
+			{"name": "returnData", "type": "bytes"}
+		]
+	}]
+}]`
+
+// NewAggregator creates an Aggregator that flushes queued calls through
+// sender and records batch→call-IDs mappings and per-call results in storage.
+func NewAggregator(cfg AggregatorConfig, sender batchSender, storage interface {
+	batchStorage
+	callResultStorage
+}) (*Aggregator, error) {
+	parsedABI, err := abi.JSON(strings.NewReader(multicall3ABIJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Multicall3 ABI: %w", err)
+	}
+	return &Aggregator{
+		cfg:     cfg,
+		sender:  sender,
+		storage: storage,
+		abi:     parsedABI,
+		pending: make(map[common.Hash][]*queuedCall),
+	}, nil
+}
+
+// AddToBatch queues a call to be settled as part of the next aggregate3 batch
+// instead of being sent as its own tx, returning a channel that receives the
+// call's CallResult once its batch is mined. Calls carrying a non-zero value
+// or exceeding MaxBatchGas on their own are rejected, since Multicall3
+// forwards msg.value per call and can't safely aggregate those.
+func (a *Aggregator) AddToBatch(ctx context.Context, to common.Address, data []byte, value *big.Int, estGas uint64) (<-chan CallResult, error) {
+	if value != nil && value.Sign() != 0 {
+		return nil, fmt.Errorf("aggregator does not support calls with non-zero value")
+	}
+	if a.cfg.MaxBatchGas != 0 && estGas > a.cfg.MaxBatchGas {
+		return nil, fmt.Errorf("call gas estimate %d exceeds MaxBatchGas %d", estGas, a.cfg.MaxBatchGas)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.nextID++
+	call := &queuedCall{
+		id:       fmt.Sprintf("%d", a.nextID),
+		to:       to,
+		data:     data,
+		estGas:   estGas,
+		resultCh: make(chan CallResult, 1),
+	}
+	a.queue = append(a.queue, call)
+
+	return call.resultCh, nil
+}
+
+// Flush builds and sends an aggregate3 tx from the queued calls, respecting
+// MaxCallsPerBatch and MaxBatchGas, and returns the number of calls flushed.
+// It's meant to be invoked periodically (every FlushInterval) by the caller's
+// monitor loop.
+func (a *Aggregator) Flush(ctx context.Context) (int, error) {
+	batch := a.dequeueBatch()
+	if len(batch) == 0 {
+		return 0, nil
+	}
+
+	calls := make([]multicall3Call, len(batch))
+	for i, call := range batch {
+		calls[i] = multicall3Call{Target: call.to, AllowFailure: true, CallData: call.data}
+	}
+
+	callData, err := a.abi.Pack("aggregate3", calls)
+	if err != nil {
+		a.requeue(batch)
+		return 0, fmt.Errorf("failed to encode aggregate3 call: %w", err)
+	}
+
+	multicallAddress := a.cfg.MulticallAddress
+	txHash, err := a.sender.Add(ctx, &multicallAddress, big.NewInt(0), callData)
+	if err != nil {
+		a.requeue(batch)
+		return 0, fmt.Errorf("failed to send aggregate3 tx: %w", err)
+	}
+
+	callIDs := make([]string, len(batch))
+	for i, call := range batch {
+		callIDs[i] = call.id
+	}
+	if err := a.storage.StoreBatch(ctx, txHash, callIDs); err != nil {
+		log.Errorf("failed to persist batch %s: %v", txHash, err)
+	}
+
+	a.pendingMu.Lock()
+	a.pending[txHash] = batch
+	a.pendingMu.Unlock()
+
+	return len(batch), nil
+}
+
+// dequeueBatch removes up to MaxCallsPerBatch queued calls from the front of
+// the queue, keeping the running gas total under MaxBatchGas.
+func (a *Aggregator) dequeueBatch() []*queuedCall {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var batch []*queuedCall
+	var gasUsed uint64
+	var remaining []*queuedCall
+
+	for _, call := range a.queue {
+		fitsCount := a.cfg.MaxCallsPerBatch == 0 || len(batch) < a.cfg.MaxCallsPerBatch
+		fitsGas := a.cfg.MaxBatchGas == 0 || gasUsed+call.estGas <= a.cfg.MaxBatchGas
+		if fitsCount && fitsGas {
+			batch = append(batch, call)
+			gasUsed += call.estGas
+		} else {
+			remaining = append(remaining, call)
+		}
+	}
+
+	a.queue = remaining
+	return batch
+}
+
+// requeue puts calls back at the front of the queue, used when Flush fails
+// to build or send the aggregate3 tx.
+func (a *Aggregator) requeue(calls []*queuedCall) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.queue = append(calls, a.queue...)
+}
+
+// OnBatchMined decodes the Multicall3.Result[] return value of a mined
+// aggregate3 tx, persists each call's CallResult in storage (so it survives
+// a restart), and fans it back to its caller's in-process result channel
+// when one is still around.
+func (a *Aggregator) OnBatchMined(ctx context.Context, txHash common.Hash, returnData []byte) error {
+	a.pendingMu.Lock()
+	batch, hasChannels := a.pending[txHash]
+	if hasChannels {
+		delete(a.pending, txHash)
+	}
+	a.pendingMu.Unlock()
+
+	callIDs, err := a.storage.GetBatch(ctx, txHash)
+	if err != nil {
+		return fmt.Errorf("no batch recorded for tx %s: %w", txHash, err)
+	}
+
+	results, err := a.decodeResults(returnData)
+	if err != nil {
+		return err
+	}
+	if len(results) != len(callIDs) {
+		return fmt.Errorf("aggregate3 returned %d results for a batch of %d calls", len(results), len(callIDs))
+	}
+
+	for i, callID := range callIDs {
+		if err := a.storage.StoreCallResult(ctx, callID, results[i]); err != nil {
+			log.Errorf("failed to persist result for call %s: %v", callID, err)
+		}
+	}
+
+	// Same-process callers still waiting on AddToBatch's channel get their
+	// result immediately; callers that resumed after a restart (no channel
+	// left) retrieve theirs through Result, backed by the storage write above.
+	if hasChannels {
+		for i, call := range batch {
+			call.resultCh <- results[i]
+			close(call.resultCh)
+		}
+	}
+	return nil
+}
+
+// Result returns the CallResult persisted for callID, so a caller can poll
+// for the outcome of a batched call even after the process that queued it
+// (and its in-process result channel) is gone.
+func (a *Aggregator) Result(ctx context.Context, callID string) (CallResult, bool, error) {
+	return a.storage.GetCallResult(ctx, callID)
+}
+
+func (a *Aggregator) decodeResults(returnData []byte) ([]CallResult, error) {
+	outputs := a.abi.Methods["aggregate3"].Outputs
+	values, err := outputs.Unpack(returnData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode aggregate3 return data: %w", err)
+	}
+
+	// Unpack returns a []interface{} wrapping a slice of an anonymous struct
+	// type abi builds via reflect.StructOf, which a named type like
+	// multicall3Result is never identical to under Go's type identity rules -
+	// a direct type assertion to []multicall3Result always fails. Copy
+	// instead copies field-by-field by name, which works across that
+	// anonymous/named struct mismatch.
+	var raw []multicall3Result
+	if err := outputs.Copy(&raw, values); err != nil {
+		return nil, fmt.Errorf("failed to copy aggregate3 return data: %w", err)
+	}
+
+	results := make([]CallResult, len(raw))
+	for i, r := range raw {
+		results[i] = CallResult{Success: r.Success, ReturnData: r.ReturnData}
+	}
+	return results, nil
+}
+
+// multicall3Call mirrors Multicall3's Call3 struct.
+type multicall3Call struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+// multicall3Result mirrors Multicall3's Result struct.
+type multicall3Result struct {
+	Success    bool
+	ReturnData []byte
+}
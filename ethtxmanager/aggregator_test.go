@@ -0,0 +1,163 @@
+package ethtxmanager
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// fakeBatchSender records the calldata passed to Add and returns a fixed hash.
+type fakeBatchSender struct {
+	lastData []byte
+	hash     common.Hash
+}
+
+func (f *fakeBatchSender) Add(_ context.Context, _ *common.Address, _ *big.Int, data []byte, _ ...[]byte) (common.Hash, error) {
+	f.lastData = data
+	return f.hash, nil
+}
+
+func newTestAggregator(t *testing.T) (*Aggregator, *fakeBatchSender, *MemStorage) {
+	t.Helper()
+	sender := &fakeBatchSender{hash: common.HexToHash("0x1234")}
+	storage, err := NewMemStorage("", false)
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	agg, err := NewAggregator(AggregatorConfig{MaxCallsPerBatch: 10}, sender, storage)
+	if err != nil {
+		t.Fatalf("failed to create aggregator: %v", err)
+	}
+	return agg, sender, storage
+}
+
+func TestAggregateCallDataRoundTrips(t *testing.T) {
+	agg, sender, _ := newTestAggregator(t)
+	ctx := context.Background()
+
+	target := common.HexToAddress("0xaaaa")
+	if _, err := agg.AddToBatch(ctx, target, []byte{0x01, 0x02}, nil, 100); err != nil {
+		t.Fatalf("AddToBatch failed: %v", err)
+	}
+
+	n, err := agg.Flush(ctx)
+	if err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("Flush flushed %d calls, want 1", n)
+	}
+
+	// A direct type assertion on Inputs.Unpack's result would always fail
+	// here (the abi package decodes a tuple[] into an anonymous struct type,
+	// never identical to multicall3Call), so decode through Unpack+Copy
+	// instead, the same way decodeResults does.
+	inputs := agg.abi.Methods["aggregate3"].Inputs
+	values, err := inputs.Unpack(sender.lastData[4:])
+	if err != nil {
+		t.Fatalf("failed to decode the calldata aggregate3 was sent: %v", err)
+	}
+	var calls []multicall3Call
+	if err := inputs.Copy(&calls, values); err != nil {
+		t.Fatalf("failed to copy the calldata aggregate3 was sent: %v", err)
+	}
+	if len(calls) != 1 {
+		t.Fatalf("decoded calls = %#v, want a single multicall3Call", calls)
+	}
+	if calls[0].Target != target {
+		t.Errorf("decoded call target = %s, want %s", calls[0].Target, target)
+	}
+	if string(calls[0].CallData) != string([]byte{0x01, 0x02}) {
+		t.Errorf("decoded call data = %x, want 0102", calls[0].CallData)
+	}
+}
+
+func TestOnBatchMinedPersistsResultsForRestartRecovery(t *testing.T) {
+	agg, sender, storage := newTestAggregator(t)
+	ctx := context.Background()
+
+	resultCh, err := agg.AddToBatch(ctx, common.HexToAddress("0xaaaa"), []byte{0x01}, nil, 100)
+	if err != nil {
+		t.Fatalf("AddToBatch failed: %v", err)
+	}
+	if _, err := agg.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	returnData, err := agg.abi.Methods["aggregate3"].Outputs.Pack([]multicall3Result{
+		{Success: true, ReturnData: []byte{0xaa}},
+	})
+	if err != nil {
+		t.Fatalf("failed to encode fake aggregate3 return data: %v", err)
+	}
+
+	if err := agg.OnBatchMined(ctx, sender.hash, returnData); err != nil {
+		t.Fatalf("OnBatchMined failed: %v", err)
+	}
+
+	select {
+	case res := <-resultCh:
+		if !res.Success || string(res.ReturnData) != "\xaa" {
+			t.Errorf("in-process result = %+v, want Success=true ReturnData=0xaa", res)
+		}
+	default:
+		t.Error("expected the in-process result channel to receive a result")
+	}
+
+	batches, err := storage.GetBatch(ctx, sender.hash)
+	if err != nil {
+		t.Fatalf("GetBatch failed: %v", err)
+	}
+	if len(batches) != 1 {
+		t.Fatalf("stored %d call IDs for the batch, want 1", len(batches))
+	}
+
+	// This is the scenario the restart case exercises: the caller has lost
+	// its in-process result channel, so the result must still be reachable
+	// by call ID through storage.
+	result, ok, err := agg.Result(ctx, batches[0])
+	if err != nil {
+		t.Fatalf("Result failed: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected a persisted result to be found for the call ID")
+	}
+	if !result.Success || string(result.ReturnData) != "\xaa" {
+		t.Errorf("persisted result = %+v, want Success=true ReturnData=0xaa", result)
+	}
+}
+
+func TestOnBatchMinedRecoveredAfterRestartStillPersistsResults(t *testing.T) {
+	agg, sender, storage := newTestAggregator(t)
+	ctx := context.Background()
+
+	// Simulate a restart: a batch was persisted before the process died, but
+	// the in-memory pending map (and the caller's result channel) is gone.
+	if err := storage.StoreBatch(ctx, sender.hash, []string{"1"}); err != nil {
+		t.Fatalf("StoreBatch failed: %v", err)
+	}
+
+	returnData, err := agg.abi.Methods["aggregate3"].Outputs.Pack([]multicall3Result{
+		{Success: false, ReturnData: []byte{0xbb}},
+	})
+	if err != nil {
+		t.Fatalf("failed to encode fake aggregate3 return data: %v", err)
+	}
+
+	if err := agg.OnBatchMined(ctx, sender.hash, returnData); err != nil {
+		t.Fatalf("OnBatchMined failed after simulated restart: %v", err)
+	}
+
+	result, ok, err := agg.Result(ctx, "1")
+	if err != nil {
+		t.Fatalf("Result failed: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected OnBatchMined to persist a result reachable after a restart, got none")
+	}
+	if result.Success || string(result.ReturnData) != "\xbb" {
+		t.Errorf("persisted result = %+v, want Success=false ReturnData=0xbb", result)
+	}
+}
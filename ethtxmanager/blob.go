@@ -0,0 +1,186 @@
+package ethtxmanager
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+)
+
+const (
+	// minBaseFeePerBlobGas is MIN_BASE_FEE_PER_BLOB_GAS from EIP-4844.
+	minBaseFeePerBlobGas = 1
+	// blobBaseFeeUpdateFraction is BLOB_BASE_FEE_UPDATE_FRACTION from EIP-4844.
+	blobBaseFeeUpdateFraction = 3338477
+	// blobReplacementBumpPercentage is the minimum percentage bump the p2p
+	// replacement rule requires for both BlobFeeCap and GasFeeCap of a blob tx,
+	// unlike the 10% bump used for regular dynamic-fee txs.
+	blobReplacementBumpPercentage = 100
+	// usableBytesPerFieldElement is the number of data bytes packed into each
+	// 32-byte BLS12-381 scalar field element. The top byte of every field
+	// element is left zeroed so the element always falls below the scalar
+	// field modulus, i.e. stays canonical.
+	usableBytesPerFieldElement = 31
+)
+
+// BlobSidecar holds everything needed to (re)broadcast a type-3 blob tx:
+// the raw blob data alongside the KZG commitments/proofs and versioned
+// hashes derived from it, so it can be persisted and replayed after a restart.
+type BlobSidecar struct {
+	Blobs       []kzg4844.Blob
+	Commitments []kzg4844.Commitment
+	Proofs      []kzg4844.Proof
+	BlobHashes  []common.Hash
+}
+
+// NewBlobSidecar computes the KZG commitments, proofs and versioned hashes
+// for blobs, ready to be attached to a type-3 tx and persisted in storage.
+func NewBlobSidecar(blobs [][]byte) (*BlobSidecar, error) {
+	sidecar := &BlobSidecar{
+		Blobs:       make([]kzg4844.Blob, len(blobs)),
+		Commitments: make([]kzg4844.Commitment, len(blobs)),
+		Proofs:      make([]kzg4844.Proof, len(blobs)),
+		BlobHashes:  make([]common.Hash, len(blobs)),
+	}
+
+	for i, raw := range blobs {
+		var blob kzg4844.Blob
+		maxBytes := (len(blob) / 32) * usableBytesPerFieldElement
+		if len(raw) > maxBytes {
+			return nil, fmt.Errorf("blob %d exceeds the maximum blob capacity of %d bytes", i, maxBytes)
+		}
+		for fieldStart, byteStart := 0, 0; byteStart < len(raw); fieldStart, byteStart = fieldStart+32, byteStart+usableBytesPerFieldElement {
+			end := byteStart + usableBytesPerFieldElement
+			if end > len(raw) {
+				end = len(raw)
+			}
+			// blob[fieldStart] stays zero so the field element never exceeds
+			// the scalar field modulus.
+			copy(blob[fieldStart+1:fieldStart+32], raw[byteStart:end])
+		}
+
+		commitment, err := kzg4844.BlobToCommitment(&blob)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute KZG commitment for blob %d: %w", i, err)
+		}
+		proof, err := kzg4844.ComputeBlobProof(&blob, commitment)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute KZG proof for blob %d: %w", i, err)
+		}
+
+		sidecar.Blobs[i] = blob
+		sidecar.Commitments[i] = commitment
+		sidecar.Proofs[i] = proof
+		sidecar.BlobHashes[i] = kzg4844.CalcBlobHashV1(sha256.New(), &commitment)
+	}
+
+	return sidecar, nil
+}
+
+// blobSidecarJSON is the on-disk representation of a BlobSidecar. Blob,
+// Commitment and Proof are fixed-size byte arrays, not slices, so
+// encoding/json doesn't base64-encode them the way it does []byte - left to
+// the default array encoding, a single blob ([131072]byte) would serialize
+// as a JSON array of 131072 per-byte integers. Hex-encoding them here keeps
+// persisted state (and any pending blob tx within it) a sane size on disk.
+type blobSidecarJSON struct {
+	Blobs       []hexutil.Bytes `json:"blobs"`
+	Commitments []hexutil.Bytes `json:"commitments"`
+	Proofs      []hexutil.Bytes `json:"proofs"`
+	BlobHashes  []common.Hash   `json:"blobHashes"`
+}
+
+// MarshalJSON hex-encodes the blob/commitment/proof arrays instead of
+// letting encoding/json expand them into per-byte integer arrays.
+func (b BlobSidecar) MarshalJSON() ([]byte, error) {
+	j := blobSidecarJSON{
+		Blobs:       make([]hexutil.Bytes, len(b.Blobs)),
+		Commitments: make([]hexutil.Bytes, len(b.Commitments)),
+		Proofs:      make([]hexutil.Bytes, len(b.Proofs)),
+		BlobHashes:  b.BlobHashes,
+	}
+	for i := range b.Blobs {
+		j.Blobs[i] = b.Blobs[i][:]
+	}
+	for i := range b.Commitments {
+		j.Commitments[i] = b.Commitments[i][:]
+	}
+	for i := range b.Proofs {
+		j.Proofs[i] = b.Proofs[i][:]
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON is the inverse of MarshalJSON.
+func (b *BlobSidecar) UnmarshalJSON(data []byte) error {
+	var j blobSidecarJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	b.Blobs = make([]kzg4844.Blob, len(j.Blobs))
+	for i, raw := range j.Blobs {
+		if len(raw) != len(b.Blobs[i]) {
+			return fmt.Errorf("blob %d has length %d, want %d", i, len(raw), len(b.Blobs[i]))
+		}
+		copy(b.Blobs[i][:], raw)
+	}
+
+	b.Commitments = make([]kzg4844.Commitment, len(j.Commitments))
+	for i, raw := range j.Commitments {
+		if len(raw) != len(b.Commitments[i]) {
+			return fmt.Errorf("commitment %d has length %d, want %d", i, len(raw), len(b.Commitments[i]))
+		}
+		copy(b.Commitments[i][:], raw)
+	}
+
+	b.Proofs = make([]kzg4844.Proof, len(j.Proofs))
+	for i, raw := range j.Proofs {
+		if len(raw) != len(b.Proofs[i]) {
+			return fmt.Errorf("proof %d has length %d, want %d", i, len(raw), len(b.Proofs[i]))
+		}
+		copy(b.Proofs[i][:], raw)
+	}
+
+	b.BlobHashes = j.BlobHashes
+	return nil
+}
+
+// blobGasPrice estimates the per-blob gas price a block with excessBlobGas
+// would charge, using the EIP-4844 fake-exponential formula:
+// fake_exponential(MIN_BASE_FEE_PER_BLOB_GAS, excess_blob_gas, BLOB_BASE_FEE_UPDATE_FRACTION).
+func blobGasPrice(excessBlobGas uint64) *big.Int {
+	return fakeExponential(
+		big.NewInt(minBaseFeePerBlobGas),
+		new(big.Int).SetUint64(excessBlobGas),
+		big.NewInt(blobBaseFeeUpdateFraction),
+	)
+}
+
+// fakeExponential approximates factor * e**(numerator / denominator) using
+// the Taylor-series expansion defined by EIP-4844, avoiding floating point.
+func fakeExponential(factor, numerator, denominator *big.Int) *big.Int {
+	output := new(big.Int)
+	numeratorAccum := new(big.Int).Mul(factor, denominator)
+
+	for i := 1; numeratorAccum.Sign() > 0; i++ {
+		output.Add(output, numeratorAccum)
+
+		numeratorAccum.Mul(numeratorAccum, numerator)
+		numeratorAccum.Div(numeratorAccum, denominator)
+		numeratorAccum.Div(numeratorAccum, big.NewInt(int64(i)))
+	}
+
+	return output.Div(output, denominator)
+}
+
+// repriceBlobTx bumps both blobFeeCap and gasFeeCap by at least
+// blobReplacementBumpPercentage, the minimum the p2p replacement rule
+// requires for blob txs, stricter than the 10% used for regular txs.
+func repriceBlobTx(blobFeeCap, gasFeeCap *big.Int) (*big.Int, *big.Int) {
+	return bumpByPercentage(blobFeeCap, blobReplacementBumpPercentage), bumpByPercentage(gasFeeCap, blobReplacementBumpPercentage)
+}
@@ -0,0 +1,87 @@
+package ethtxmanager
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestBlobSidecarJSONRoundTrips(t *testing.T) {
+	sidecar, err := NewBlobSidecar([][]byte{{0x01, 0x02, 0x03}, {0xaa}})
+	if err != nil {
+		t.Fatalf("NewBlobSidecar failed: %v", err)
+	}
+
+	raw, err := json.Marshal(sidecar)
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+
+	var got BlobSidecar
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("UnmarshalJSON failed: %v", err)
+	}
+
+	if len(got.Blobs) != len(sidecar.Blobs) || got.Blobs[0] != sidecar.Blobs[0] || got.Blobs[1] != sidecar.Blobs[1] {
+		t.Errorf("round-tripped Blobs = %v, want %v", got.Blobs, sidecar.Blobs)
+	}
+	if len(got.Commitments) != len(sidecar.Commitments) || got.Commitments[0] != sidecar.Commitments[0] {
+		t.Errorf("round-tripped Commitments = %v, want %v", got.Commitments, sidecar.Commitments)
+	}
+	if len(got.Proofs) != len(sidecar.Proofs) || got.Proofs[0] != sidecar.Proofs[0] {
+		t.Errorf("round-tripped Proofs = %v, want %v", got.Proofs, sidecar.Proofs)
+	}
+	if len(got.BlobHashes) != len(sidecar.BlobHashes) || got.BlobHashes[0] != sidecar.BlobHashes[0] {
+		t.Errorf("round-tripped BlobHashes = %v, want %v", got.BlobHashes, sidecar.BlobHashes)
+	}
+}
+
+func TestFakeExponentialZeroNumeratorReturnsFactor(t *testing.T) {
+	got := fakeExponential(big.NewInt(1), big.NewInt(0), big.NewInt(3338477))
+	if got.Cmp(big.NewInt(1)) != 0 {
+		t.Errorf("fakeExponential(1, 0, d) = %s, want 1 (e**0 == 1)", got)
+	}
+}
+
+func TestFakeExponentialIsMonotonicInNumerator(t *testing.T) {
+	factor := big.NewInt(1)
+	denominator := big.NewInt(3338477)
+
+	prev := fakeExponential(factor, big.NewInt(0), denominator)
+	for _, numerator := range []int64{1000, 10000, 100000, 1000000, 10000000} {
+		got := fakeExponential(factor, big.NewInt(numerator), denominator)
+		if got.Cmp(prev) < 0 {
+			t.Errorf("fakeExponential(1, %d, d) = %s, expected >= previous value %s", numerator, got, prev)
+		}
+		prev = got
+	}
+}
+
+func TestBlobGasPriceAtZeroExcessIsMinimum(t *testing.T) {
+	got := blobGasPrice(0)
+	if got.Cmp(big.NewInt(minBaseFeePerBlobGas)) != 0 {
+		t.Errorf("blobGasPrice(0) = %s, want the minimum base fee per blob gas %d", got, minBaseFeePerBlobGas)
+	}
+}
+
+func TestBlobGasPriceIncreasesWithExcessBlobGas(t *testing.T) {
+	low := blobGasPrice(1_000_000)
+	high := blobGasPrice(10_000_000)
+	if high.Cmp(low) <= 0 {
+		t.Errorf("blobGasPrice(10_000_000) = %s, want strictly greater than blobGasPrice(1_000_000) = %s", high, low)
+	}
+}
+
+func TestRepriceBlobTxBumpsByAtLeast100Percent(t *testing.T) {
+	blobFeeCap := big.NewInt(100)
+	gasFeeCap := big.NewInt(200)
+
+	newBlobFeeCap, newGasFeeCap := repriceBlobTx(blobFeeCap, gasFeeCap)
+
+	if newBlobFeeCap.Cmp(new(big.Int).Mul(blobFeeCap, big.NewInt(2))) < 0 {
+		t.Errorf("repriceBlobTx bumped blobFeeCap to %s, want at least %d (100%% bump)", newBlobFeeCap, 200)
+	}
+	if newGasFeeCap.Cmp(new(big.Int).Mul(gasFeeCap, big.NewInt(2))) < 0 {
+		t.Errorf("repriceBlobTx bumped gasFeeCap to %s, want at least %d (100%% bump)", newGasFeeCap, 400)
+	}
+}
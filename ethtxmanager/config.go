@@ -23,6 +23,11 @@ type Config struct {
 	// to be read in order to provide the private keys to sign the L1 txs
 	PrivateKeys []types.KeystoreFileConfig `mapstructure:"PrivateKeys"`
 
+	// Signers defines the remote signing backends (Web3Signer, AWS KMS, GCP KMS)
+	// available in addition to PrivateKeys, keyed by the address they sign for.
+	// This allows operators to run the manager without keeping raw private keys on disk.
+	Signers []SignerConfig `mapstructure:"Signers"`
+
 	// ForcedGas is the amount of gas to be forced in case of gas estimation error
 	ForcedGas uint64 `mapstructure:"ForcedGas"`
 
@@ -58,6 +63,61 @@ type Config struct {
 	// max gas price limit: 110
 	// tx gas price = 110
 	MaxGasPriceLimit uint64 `mapstructure:"MaxGasPriceLimit"`
+
+	// MaxBlobGasPriceLimit mirrors MaxGasPriceLimit for the per-blob gas price
+	// of EIP-4844 blob txs, default value is 0, which means no limit.
+	MaxBlobGasPriceLimit uint64 `mapstructure:"MaxBlobGasPriceLimit"`
+
+	// BlobGasPriceMarginFactor mirrors GasPriceMarginFactor for the per-blob
+	// gas price of EIP-4844 blob txs, default value is 1.
+	BlobGasPriceMarginFactor float64 `mapstructure:"BlobGasPriceMarginFactor"`
+
+	// TxType selects the transaction pricing model used to send L1 txs,
+	// either TxTypeLegacy (gas price) or TxTypeDynamic (EIP-1559 fee cap / tip cap).
+	TxType TxType `mapstructure:"TxType"`
+
+	// GasTipCap is the minimum priority fee (tip) suggested to the network
+	// when TxType is TxTypeDynamic, used as the floor a GasPricer can adjust from.
+	GasTipCap uint64 `mapstructure:"GasTipCap"`
+
+	// MaxPriorityFeePerGas caps the priority fee (tip) a GasPricer is allowed to
+	// suggest, regardless of how congested the network appears, default value is 0,
+	// which means no limit.
+	MaxPriorityFeePerGas uint64 `mapstructure:"MaxPriorityFeePerGas"`
+
+	// MaxFeePerGas caps the total fee per gas (base fee + tip) that dynamic-fee txs
+	// are allowed to use, default value is 0, which means no limit.
+	MaxFeePerGas uint64 `mapstructure:"MaxFeePerGas"`
+
+	// BaseFeeMultiplier is used to multiply the latest base fee when computing the
+	// fee cap for a dynamic-fee tx, giving it headroom to survive a few base fee
+	// increases before it needs to be repriced, default value is 2.
+	//
+	// ex:
+	// base fee: 100
+	// BaseFeeMultiplier: 2
+	// fee cap (before adding the tip) = 200
+	BaseFeeMultiplier float64 `mapstructure:"BaseFeeMultiplier"`
+
+	// GasPriceOracle configures the GasPricer implementation used to sample
+	// eth_feeHistory and keep priority fees within GasTipCap/MaxPriorityFeePerGas.
+	GasPriceOracle GasPriceOracleConfig `mapstructure:"GasPriceOracle"`
+
+	// RPC configures the JSON-RPC admin endpoint used by other processes
+	// (e.g. sequencer/aggregator) to submit and manage L1 txs against a
+	// shared ethtxmanager instance.
+	RPC RPCConfig `mapstructure:"RPC"`
+
+	// Aggregator configures the optional Multicall3-based batching subsystem
+	// used by AddToBatch to settle many independent L1 calls (e.g. bridge
+	// claims) through a single shared tx.
+	Aggregator AggregatorConfig `mapstructure:"Aggregator"`
+
+	// Dispatch configures how new txs are load-balanced across the signers
+	// configured in PrivateKeys/Signers, instead of serializing through a
+	// single account.
+	Dispatch DispatchConfig `mapstructure:"Dispatch"`
+
 	// PersistenceFilename is the filename to store the memory storage
 	PersistenceFilename string `mapstructure:"PersistenceFilename"`
 	// ReadPendingL1Txs is a flag to enable the reading of pending L1 txs
@@ -0,0 +1,663 @@
+package ethtxmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+
+	"github.com/0xPolygonHermez/zkevm-ethtx-manager/log"
+)
+
+// ethClient is the subset of an ethereum client the Client needs to build,
+// send and monitor L1 txs, satisfied by etherman.Client.
+type ethClient interface {
+	feeHistoryClient
+	nonceClient
+
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+// Client is the ethtxmanager's public entry point: it builds, signs, sends
+// and monitors L1 txs on behalf of the configured signers, optionally
+// batching independent calls through its Aggregator and exposing itself to
+// other processes over RPCServer.
+type Client struct {
+	cfg     Config
+	client  ethClient
+	storage Storage
+	chainID *big.Int
+
+	gasPricer  GasPricer
+	signers    *SignerManager
+	nonces     *NonceManager
+	aggregator *Aggregator
+	rpc        *RPCServer
+
+	mu                  sync.Mutex
+	gasPriceOverride    *big.Int
+	maxGasPriceOverride *big.Int
+}
+
+// New creates a Client that sends txs on behalf of signers against client,
+// persisting its state in storage.
+func New(ctx context.Context, cfg Config, client ethClient, storage Storage, chainID *big.Int, signers ...Signer) (*Client, error) {
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("at least one signer must be configured")
+	}
+
+	addresses := make([]common.Address, len(signers))
+	for i, signer := range signers {
+		addresses[i] = signer.Address()
+	}
+
+	nonces, err := NewNonceManager(ctx, cfg.Dispatch, client, addresses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize nonce manager: %w", err)
+	}
+
+	c := &Client{
+		cfg:       cfg,
+		client:    client,
+		storage:   storage,
+		chainID:   chainID,
+		gasPricer: NewFeeHistoryGasPricer(client, cfg),
+		signers:   NewSignerManager(chainID, signers...),
+		nonces:    nonces,
+	}
+
+	aggregator, err := NewAggregator(cfg.Aggregator, c, storage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize aggregator: %w", err)
+	}
+	c.aggregator = aggregator
+	c.rpc = NewRPCServer(cfg.RPC, c)
+
+	return c, nil
+}
+
+// Add builds, signs and sends a tx to the network, selecting a signer
+// according to DispatchConfig.Strategy and reserving its next nonce, then
+// tracks it in storage so the monitor loop can reprice and confirm it. When
+// blobs is non-empty, the tx is sent as an EIP-4844 blob tx carrying them.
+func (c *Client) Add(ctx context.Context, to *common.Address, value *big.Int, data []byte, blobs ...[]byte) (common.Hash, error) {
+	if value == nil {
+		value = big.NewInt(0)
+	}
+
+	tag := ""
+	if to != nil {
+		tag = to.Hex()
+	}
+	from, nonce, err := c.nonces.SelectAndReserve(tag)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to reserve a signer nonce: %w", err)
+	}
+
+	opts, err := c.signers.TransactOpts(from)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to resolve signer for %s: %w", from, err)
+	}
+
+	gas, err := c.client.EstimateGas(ctx, ethereum.CallMsg{From: from, To: to, Value: value, Data: data})
+	if err != nil {
+		if c.cfg.ForcedGas == 0 {
+			return common.Hash{}, fmt.Errorf("failed to estimate gas: %w", err)
+		}
+		gas = c.cfg.ForcedGas
+	}
+
+	monitoredTx := MonitoredTx{
+		From:   from,
+		To:     to,
+		Nonce:  nonce,
+		Value:  value,
+		Data:   data,
+		Gas:    gas,
+		TxType: c.cfg.TxType,
+		Status: MonitoredStatusCreated,
+	}
+
+	var sidecar *BlobSidecar
+	if len(blobs) > 0 {
+		sidecar, err = NewBlobSidecar(blobs)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to build blob sidecar: %w", err)
+		}
+		monitoredTx.BlobSidecar = sidecar
+	}
+
+	tx, err := c.buildTx(ctx, monitoredTx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	signedTx, err := opts.Signer(from, tx)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to sign tx: %w", err)
+	}
+
+	if err := c.client.SendTransaction(ctx, signedTx); err != nil {
+		return common.Hash{}, fmt.Errorf("failed to send tx: %w", err)
+	}
+
+	monitoredTx.ID = signedTx.Hash()
+	monitoredTx.GasPrice = legacyGasPrice(monitoredTx, signedTx)
+	monitoredTx.GasTipCap = signedTx.GasTipCap()
+	monitoredTx.GasFeeCap = signedTx.GasFeeCap()
+	monitoredTx.BlobFeeCap = signedTx.BlobGasFeeCap()
+	monitoredTx.Status = MonitoredStatusSent
+	monitoredTx.History = []common.Hash{signedTx.Hash()}
+	monitoredTx.LastSentAt = time.Now()
+
+	if err := c.storage.Add(ctx, monitoredTx); err != nil {
+		log.Errorf("failed to persist monitored tx %s: %v", monitoredTx.ID, err)
+	}
+
+	return signedTx.Hash(), nil
+}
+
+// legacyGasPrice returns the legacy gas price of signedTx, or nil for a
+// dynamic-fee/blob tx.
+func legacyGasPrice(tx MonitoredTx, signedTx *types.Transaction) *big.Int {
+	if tx.TxType != TxTypeLegacy || tx.BlobSidecar != nil {
+		return nil
+	}
+	return signedTx.GasPrice()
+}
+
+// buildTx constructs the unsigned tx for monitoredTx according to its TxType
+// and whether it carries a BlobSidecar, applying the configured gas price
+// limits.
+func (c *Client) buildTx(ctx context.Context, tx MonitoredTx) (*types.Transaction, error) {
+	switch {
+	case tx.BlobSidecar != nil:
+		return c.buildBlobTx(ctx, tx)
+	case tx.TxType == TxTypeDynamic:
+		return c.buildDynamicFeeTx(ctx, tx)
+	default:
+		return c.buildLegacyTx(ctx, tx)
+	}
+}
+
+func (c *Client) buildLegacyTx(ctx context.Context, tx MonitoredTx) (*types.Transaction, error) {
+	gasPrice, err := c.suggestedGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newLegacyTxFromFields(tx, gasPrice), nil
+}
+
+func (c *Client) buildDynamicFeeTx(ctx context.Context, tx MonitoredTx) (*types.Transaction, error) {
+	tipCap, feeCap, err := c.suggestedFees(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.newDynamicFeeTxFromFields(tx, tipCap, feeCap), nil
+}
+
+func (c *Client) buildBlobTx(ctx context.Context, tx MonitoredTx) (*types.Transaction, error) {
+	tipCap, feeCap, err := c.suggestedFees(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := c.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch latest header for blob gas pricing: %w", err)
+	}
+	blobFeeCap := c.capBlobFeePrice(blobGasPrice(excessBlobGas(header)))
+
+	return c.newBlobTxFromFields(tx, tipCap, feeCap, blobFeeCap)
+}
+
+// newBlobTxFromFields constructs a blob tx for tx's nonce/recipient/value and
+// BlobSidecar carrying tipCap/feeCap/blobFeeCap, without consulting the
+// GasPricer or refetching blob gas pricing - used both for the initial send
+// and for a repriced resend (which bumps the existing caps instead of
+// resampling the latest header).
+func (c *Client) newBlobTxFromFields(tx MonitoredTx, tipCap, feeCap, blobFeeCap *big.Int) (*types.Transaction, error) {
+	if tx.To == nil {
+		return nil, fmt.Errorf("blob txs require a recipient address")
+	}
+	return types.NewTx(&types.BlobTx{
+		ChainID:    uint256FromBig(c.chainID),
+		Nonce:      tx.Nonce,
+		To:         *tx.To,
+		Value:      uint256FromBig(tx.Value),
+		Gas:        tx.Gas,
+		GasTipCap:  uint256FromBig(tipCap),
+		GasFeeCap:  uint256FromBig(feeCap),
+		Data:       tx.Data,
+		BlobFeeCap: uint256FromBig(blobFeeCap),
+		BlobHashes: tx.BlobSidecar.BlobHashes,
+		Sidecar: &types.BlobTxSidecar{
+			Blobs:       tx.BlobSidecar.Blobs,
+			Commitments: tx.BlobSidecar.Commitments,
+			Proofs:      tx.BlobSidecar.Proofs,
+		},
+	}), nil
+}
+
+// newLegacyTxFromFields constructs a legacy tx for tx's nonce/recipient/value
+// carrying gasPrice, without consulting the GasPricer - used both for the
+// initial send (priced by buildLegacyTx) and for a repriced resend (priced by
+// repriceTx from tx's own already-set GasPrice).
+func newLegacyTxFromFields(tx MonitoredTx, gasPrice *big.Int) *types.Transaction {
+	return types.NewTx(&types.LegacyTx{
+		Nonce:    tx.Nonce,
+		To:       tx.To,
+		Value:    tx.Value,
+		Gas:      tx.Gas,
+		GasPrice: gasPrice,
+		Data:     tx.Data,
+	})
+}
+
+// newDynamicFeeTxFromFields constructs a dynamic-fee tx for tx's
+// nonce/recipient/value carrying tipCap/feeCap, without consulting the
+// GasPricer - used both for the initial send and for a repriced resend.
+func (c *Client) newDynamicFeeTxFromFields(tx MonitoredTx, tipCap, feeCap *big.Int) *types.Transaction {
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   c.chainID,
+		Nonce:     tx.Nonce,
+		To:        tx.To,
+		Value:     tx.Value,
+		Gas:       tx.Gas,
+		GasTipCap: tipCap,
+		GasFeeCap: feeCap,
+		Data:      tx.Data,
+	})
+}
+
+// repriceTx builds the next, bumped version of a stuck tx from its own
+// already-set price fields, rather than asking the GasPricer for a fresh
+// suggestion (which could come back unchanged or only marginally higher). A
+// blob tx bumps BlobFeeCap and GasFeeCap by at least
+// blobReplacementBumpPercentage, the minimum the p2p replacement rule
+// requires. A tx whose price fields were never set by buildTx (e.g. a
+// gap-filling self-transfer) is priced for the first time instead, since
+// there is nothing yet to bump.
+func (c *Client) repriceTx(ctx context.Context, tx MonitoredTx) (*types.Transaction, error) {
+	switch {
+	case tx.BlobSidecar != nil:
+		if tx.BlobFeeCap == nil || tx.GasFeeCap == nil || tx.GasTipCap == nil {
+			return c.buildBlobTx(ctx, tx)
+		}
+		blobFeeCap, gasFeeCap := repriceBlobTx(tx.BlobFeeCap, tx.GasFeeCap)
+		return c.newBlobTxFromFields(tx, tx.GasTipCap, gasFeeCap, blobFeeCap)
+	case tx.TxType == TxTypeDynamic:
+		if tx.GasTipCap == nil || tx.GasFeeCap == nil {
+			return c.buildDynamicFeeTx(ctx, tx)
+		}
+		tipCap, feeCap := c.gasPricer.Reprice(tx.GasTipCap, tx.GasFeeCap)
+		return c.newDynamicFeeTxFromFields(tx, tipCap, feeCap), nil
+	default:
+		if tx.GasPrice == nil {
+			return c.buildLegacyTx(ctx, tx)
+		}
+		return newLegacyTxFromFields(tx, bumpByPercentage(tx.GasPrice, minRPCBumpPercentage)), nil
+	}
+}
+
+// uint256FromBig converts b to a *uint256.Int, as required by the fee/value
+// fields of types.BlobTx.
+func uint256FromBig(b *big.Int) *uint256.Int {
+	v, _ := uint256.FromBig(b)
+	return v
+}
+
+// excessBlobGas returns header.ExcessBlobGas, or 0 for a pre-Cancun header.
+func excessBlobGas(header *types.Header) uint64 {
+	if header.ExcessBlobGas == nil {
+		return 0
+	}
+	return *header.ExcessBlobGas
+}
+
+// suggestedGasPrice applies GasPriceMarginFactor/MaxGasPriceLimit on top of
+// the GasPricer's suggestion for a legacy tx.
+func (c *Client) suggestedGasPrice(ctx context.Context) (*big.Int, error) {
+	c.mu.Lock()
+	override := c.gasPriceOverride
+	c.mu.Unlock()
+	if override != nil {
+		return override, nil
+	}
+
+	gasPrice, err := c.gasPricer.SuggestedGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suggested gas price: %w", err)
+	}
+	gasPrice = mulFloat(gasPrice, c.cfg.GasPriceMarginFactor)
+	return c.capGasPrice(gasPrice), nil
+}
+
+// suggestedFees applies MaxPriorityFeePerGas/MaxFeePerGas on top of the
+// GasPricer's suggestion for a dynamic-fee or blob tx, honoring
+// SetGasPrice's override the same way suggestedGasPrice does for a legacy
+// tx - feeCap is the dynamic-fee/blob equivalent of a legacy gas price (the
+// total a sender is willing to pay per gas), so it's the field the override
+// replaces; GasTipCap (the reward to the block proposer) is left to the
+// GasPricer either way.
+func (c *Client) suggestedFees(ctx context.Context) (*big.Int, *big.Int, error) {
+	tipCap, feeCap, err := c.gasPricer.SuggestedFees(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get suggested fees: %w", err)
+	}
+
+	c.mu.Lock()
+	override := c.gasPriceOverride
+	c.mu.Unlock()
+	if override != nil {
+		feeCap = override
+	}
+
+	return tipCap, c.capGasPrice(feeCap), nil
+}
+
+// capGasPrice clamps gasPrice to MaxGasPriceLimit, when configured.
+func (c *Client) capGasPrice(gasPrice *big.Int) *big.Int {
+	c.mu.Lock()
+	max := c.maxGasPriceOverride
+	c.mu.Unlock()
+	if max == nil && c.cfg.MaxGasPriceLimit != 0 {
+		max = new(big.Int).SetUint64(c.cfg.MaxGasPriceLimit)
+	}
+	if max != nil && gasPrice.Cmp(max) > 0 {
+		return max
+	}
+	return gasPrice
+}
+
+// capBlobFeePrice clamps blobFeePrice to MaxBlobGasPriceLimit, when configured.
+func (c *Client) capBlobFeePrice(blobFeePrice *big.Int) *big.Int {
+	blobFeePrice = mulFloat(blobFeePrice, c.cfg.BlobGasPriceMarginFactor)
+	if c.cfg.MaxBlobGasPriceLimit != 0 {
+		max := new(big.Int).SetUint64(c.cfg.MaxBlobGasPriceLimit)
+		if blobFeePrice.Cmp(max) > 0 {
+			return max
+		}
+	}
+	return blobFeePrice
+}
+
+// AddToBatch queues a call to be settled through the Aggregator as part of a
+// shared Multicall3 tx instead of its own, see Aggregator.AddToBatch.
+func (c *Client) AddToBatch(ctx context.Context, to common.Address, data []byte, value *big.Int, estGas uint64) (<-chan CallResult, error) {
+	return c.aggregator.AddToBatch(ctx, to, data, value, estGas)
+}
+
+// Result returns the MonitoredTx tracked under hash.
+func (c *Client) Result(ctx context.Context, hash common.Hash) (interface{}, error) {
+	return c.storage.Get(ctx, hash)
+}
+
+// ResultsByStatus returns every MonitoredTx currently in one of statuses.
+func (c *Client) ResultsByStatus(ctx context.Context, statuses []string) ([]interface{}, error) {
+	typed := make([]MonitoredTxStatus, len(statuses))
+	for i, status := range statuses {
+		typed[i] = MonitoredTxStatus(status)
+	}
+	txs, err := c.storage.GetByStatus(ctx, typed)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]interface{}, len(txs))
+	for i, tx := range txs {
+		results[i] = tx
+	}
+	return results, nil
+}
+
+// Remove stops tracking the tx identified by hash.
+func (c *Client) Remove(ctx context.Context, hash common.Hash) error {
+	return c.storage.Remove(ctx, hash)
+}
+
+// SetGasPrice overrides the gas price/fee cap suggested for every subsequent
+// tx, ignoring the GasPricer, until cleared with a nil gasPrice.
+func (c *Client) SetGasPrice(gasPrice *big.Int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gasPriceOverride = gasPrice
+}
+
+// SetMaxGasPrice overrides MaxGasPriceLimit for every subsequent tx, until
+// cleared with a nil maxGasPrice.
+func (c *Client) SetMaxGasPrice(maxGasPrice *big.Int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxGasPriceOverride = maxGasPrice
+}
+
+// Start runs the RPCServer (and, if configured, its gRPC surface) alongside
+// the monitor loop until ctx is cancelled. The monitor loop confirms nonces
+// for mined txs, reprices and resends txs stuck past WaitTxToBeMined,
+// unsticks signers whose oldest nonce has gone stale, routes Aggregator
+// batch results, and periodically flushes queued batched calls.
+func (c *Client) Start(ctx context.Context) {
+	go func() {
+		if err := c.rpc.Start(ctx); err != nil {
+			log.Errorf("ethtxmanager RPC server stopped: %v", err)
+		}
+	}()
+	go func() {
+		if err := c.rpc.StartGRPC(ctx); err != nil {
+			log.Errorf("ethtxmanager gRPC server stopped: %v", err)
+		}
+	}()
+
+	ticker := time.NewTicker(tickInterval(c.cfg.FrequencyToMonitorTxs.Duration))
+	defer ticker.Stop()
+
+	flushTicker := time.NewTicker(tickInterval(c.cfg.Aggregator.FlushInterval.Duration))
+	defer flushTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.monitorTxs(ctx)
+			c.monitorNonces(ctx)
+		case <-flushTicker.C:
+			if _, err := c.aggregator.Flush(ctx); err != nil {
+				log.Errorf("failed to flush aggregator batch: %v", err)
+			}
+		}
+	}
+}
+
+// tickInterval returns d, or a 1-second fallback when d is non-positive, so a
+// zero-value duration in Config can't make time.NewTicker panic.
+func tickInterval(d time.Duration) time.Duration {
+	if d <= 0 {
+		return time.Second
+	}
+	return d
+}
+
+// monitorTxs checks every tracked, unconfirmed tx for a receipt, confirming
+// or repricing it as needed.
+func (c *Client) monitorTxs(ctx context.Context) {
+	txs, err := c.storage.GetByStatus(ctx, []MonitoredTxStatus{MonitoredStatusSent})
+	if err != nil {
+		log.Errorf("failed to load sent txs: %v", err)
+		return
+	}
+
+	for _, tx := range txs {
+		c.monitorTx(ctx, tx)
+	}
+}
+
+// monitorTx confirms tx if a receipt is now available for it, otherwise
+// reprices and resends it once it's been outstanding for longer than
+// Config.WaitTxToBeMined; staleness-based gap-filling for a signer's oldest
+// unconfirmed nonce is handled separately by monitorNonces, since it only
+// depends on the signer's address, not on any individual tracked tx.
+func (c *Client) monitorTx(ctx context.Context, tx MonitoredTx) {
+	receipt, err := c.client.TransactionReceipt(ctx, tx.LatestHash())
+	if err != nil && !errors.Is(err, ethereum.NotFound) {
+		return
+	}
+	if receipt != nil {
+		c.confirmTx(ctx, tx, receipt)
+		return
+	}
+
+	if time.Since(tx.LastSentAt) < c.cfg.WaitTxToBeMined.Duration {
+		return
+	}
+	c.repriceAndResend(ctx, tx)
+}
+
+// repriceAndResend builds a bumped replacement for tx via repriceTx, signs
+// and sends it, and records the new version in tx's History so a future
+// receipt lookup uses the latest hash.
+func (c *Client) repriceAndResend(ctx context.Context, tx MonitoredTx) {
+	newTx, err := c.repriceTx(ctx, tx)
+	if err != nil {
+		log.Errorf("failed to reprice stuck tx %s: %v", tx.ID, err)
+		return
+	}
+
+	opts, err := c.signers.TransactOpts(tx.From)
+	if err != nil {
+		log.Errorf("failed to resolve signer to reprice tx %s: %v", tx.ID, err)
+		return
+	}
+	signedTx, err := opts.Signer(tx.From, newTx)
+	if err != nil {
+		log.Errorf("failed to sign repriced tx %s: %v", tx.ID, err)
+		return
+	}
+	if err := c.client.SendTransaction(ctx, signedTx); err != nil {
+		log.Errorf("failed to send repriced tx %s: %v", tx.ID, err)
+		return
+	}
+
+	tx.GasPrice = legacyGasPrice(tx, signedTx)
+	tx.GasTipCap = signedTx.GasTipCap()
+	tx.GasFeeCap = signedTx.GasFeeCap()
+	tx.BlobFeeCap = signedTx.BlobGasFeeCap()
+	tx.History = append(tx.History, signedTx.Hash())
+	tx.LastSentAt = time.Now()
+
+	if err := c.storage.Update(ctx, tx); err != nil {
+		log.Errorf("failed to persist repriced tx %s: %v", tx.ID, err)
+	}
+}
+
+func (c *Client) confirmTx(ctx context.Context, tx MonitoredTx, receipt *types.Receipt) {
+	tx.Status = MonitoredStatusMined
+	if receipt.Status == types.ReceiptStatusFailed {
+		tx.Status = MonitoredStatusFailed
+	}
+	if err := c.storage.Update(ctx, tx); err != nil {
+		log.Errorf("failed to persist confirmed tx %s: %v", tx.ID, err)
+	}
+	c.nonces.ConfirmNonce(tx.From, tx.Nonce)
+
+	if tx.To == nil || tx.To.Cmp(c.cfg.Aggregator.MulticallAddress) != 0 {
+		return
+	}
+	returnData, err := c.client.CallContract(ctx, ethereum.CallMsg{From: tx.From, To: tx.To, Data: tx.Data}, receipt.BlockNumber)
+	if err != nil {
+		log.Errorf("failed to replay aggregate3 call for batch %s: %v", tx.ID, err)
+		return
+	}
+	if err := c.aggregator.OnBatchMined(ctx, tx.ID, returnData); err != nil {
+		log.Errorf("failed to route results for batch %s: %v", tx.ID, err)
+	}
+}
+
+// monitorNonces unsticks, via a self-transfer, any signer whose oldest
+// unconfirmed nonce has gone stale with no tracked tx left to retry (e.g.
+// after a restart lost the in-memory bookkeeping tying it to one).
+func (c *Client) monitorNonces(ctx context.Context) {
+	for _, address := range c.nonces.addresses {
+		nonce, stale, err := c.nonces.StaleNonce(ctx, address)
+		if err != nil {
+			log.Errorf("failed to check nonce staleness for %s: %v", address, err)
+			continue
+		}
+		if !stale {
+			continue
+		}
+
+		tracked, err := c.gapFillTracked(ctx, address, nonce)
+		if err != nil {
+			log.Errorf("failed to check for a tracked gap-fill for %s nonce %d: %v", address, nonce, err)
+			continue
+		}
+		if tracked {
+			continue
+		}
+
+		if err := c.fillGap(ctx, address, nonce); err != nil {
+			log.Errorf("failed to fill stale nonce %d for %s: %v", nonce, address, err)
+		}
+	}
+}
+
+// gapFillTracked reports whether a gap-filling self-transfer for address's
+// nonce is already tracked and still outstanding. StaleNonce keeps reporting
+// the same nonce as stale on every tick until ConfirmNonce eventually clears
+// it, so without this check monitorNonces would fillGap again on every tick
+// before the previous self-transfer is even mined; once one is tracked, its
+// own receipt/reprice lifecycle is handled by monitorTx like any other tx.
+func (c *Client) gapFillTracked(ctx context.Context, address common.Address, nonce uint64) (bool, error) {
+	txs, err := c.storage.GetByStatus(ctx, []MonitoredTxStatus{MonitoredStatusSent})
+	if err != nil {
+		return false, err
+	}
+	for _, tx := range txs {
+		if tx.From == address && tx.Nonce == nonce {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fillGap issues a self-transfer to unstick address's stale nonce and tracks
+// the resulting tx as a normal MonitoredTx, so the existing monitorTxs →
+// confirmTx pipeline advances NonceManager's confirmed watermark once it's
+// mined, instead of the hash being fire-and-forgotten with nothing left to
+// later call ConfirmNonce for it.
+func (c *Client) fillGap(ctx context.Context, address common.Address, nonce uint64) error {
+	hash, err := c.nonces.FillGap(ctx, address, nonce)
+	if err != nil {
+		return fmt.Errorf("failed to send gap-filling self-transfer: %w", err)
+	}
+
+	tx := MonitoredTx{
+		ID:         hash,
+		From:       address,
+		To:         &address,
+		Nonce:      nonce,
+		Value:      big.NewInt(0),
+		Gas:        params.TxGas,
+		TxType:     TxTypeLegacy,
+		Status:     MonitoredStatusSent,
+		History:    []common.Hash{hash},
+		LastSentAt: time.Now(),
+	}
+	if err := c.storage.Add(ctx, tx); err != nil {
+		return fmt.Errorf("failed to persist gap-filling tx %s: %w", hash, err)
+	}
+	return nil
+}
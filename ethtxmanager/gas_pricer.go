@@ -0,0 +1,146 @@
+package ethtxmanager
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+)
+
+// TxType defines the transaction pricing model used to send L1 txs.
+type TxType string
+
+const (
+	// TxTypeLegacy sends txs priced with a single gas price (pre EIP-1559).
+	TxTypeLegacy TxType = "legacy"
+	// TxTypeDynamic sends txs priced with a GasTipCap/GasFeeCap pair (EIP-1559).
+	TxTypeDynamic TxType = "dynamic"
+
+	// minRPCBumpPercentage is the minimum percentage increase the L1 node requires
+	// between a transaction and the one replacing it, as enforced by the txpool.
+	minRPCBumpPercentage = 10
+)
+
+// GasPriceOracleConfig configures a feeHistory-based GasPricer.
+type GasPriceOracleConfig struct {
+	// FeeHistoryBlockCount is the number of trailing blocks sampled from
+	// eth_feeHistory when estimating the priority fee.
+	FeeHistoryBlockCount uint64 `mapstructure:"FeeHistoryBlockCount"`
+	// RewardPercentile is the reward percentile requested from eth_feeHistory,
+	// a higher percentile follows what well-paying txs are tipping.
+	RewardPercentile float64 `mapstructure:"RewardPercentile"`
+	// RepriceBumpPercentage is the percentage applied to the tip and fee cap of
+	// a stuck dynamic-fee tx when it is resent, it must be at least
+	// minRPCBumpPercentage or the replacement will be rejected by the node.
+	RepriceBumpPercentage uint64 `mapstructure:"RepriceBumpPercentage"`
+}
+
+// feeHistoryClient is the subset of the ethereum client used to sample
+// eth_feeHistory, satisfied by etherman.Client.
+type feeHistoryClient interface {
+	FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error)
+}
+
+// GasPricer suggests gas pricing for the txs sent by the ethtxmanager,
+// implementations are free to keep internal state (e.g. a cached base fee)
+// as long as SuggestedGasPrice/SuggestedFees are safe for concurrent use.
+type GasPricer interface {
+	// SuggestedGasPrice returns the gas price to use for a legacy tx.
+	SuggestedGasPrice(ctx context.Context) (*big.Int, error)
+	// SuggestedFees returns the tip cap and fee cap to use for a dynamic-fee tx.
+	SuggestedFees(ctx context.Context) (gasTipCap *big.Int, gasFeeCap *big.Int, err error)
+	// Reprice bumps the tip and fee cap of a stuck dynamic-fee tx, respecting the
+	// RPC minimum bump percentage required to replace a pending tx.
+	Reprice(gasTipCap *big.Int, gasFeeCap *big.Int) (*big.Int, *big.Int)
+}
+
+// feeHistoryGasPricer is a GasPricer that samples eth_feeHistory over a
+// configurable window and keeps the priority fee between GasTipCap and
+// MaxPriorityFeePerGas, mirroring how go-ethereum's miner config keeps
+// GasPrice between GasFloor and GasCeiling.
+type feeHistoryGasPricer struct {
+	client feeHistoryClient
+	cfg    Config
+}
+
+// NewFeeHistoryGasPricer creates a GasPricer that samples eth_feeHistory
+// through client to suggest priority fees for dynamic-fee txs.
+func NewFeeHistoryGasPricer(client feeHistoryClient, cfg Config) GasPricer {
+	return &feeHistoryGasPricer{client: client, cfg: cfg}
+}
+
+// SuggestedGasPrice returns the fee cap, since a legacy tx's single gas price
+// has no notion of a base fee separate from the price itself - it must cover
+// the base fee (already folded into feeCap) to be accepted at all.
+func (p *feeHistoryGasPricer) SuggestedGasPrice(ctx context.Context) (*big.Int, error) {
+	_, feeCap, err := p.SuggestedFees(ctx)
+	return feeCap, err
+}
+
+// SuggestedFees samples eth_feeHistory over GasPriceOracle.FeeHistoryBlockCount
+// blocks at GasPriceOracle.RewardPercentile, clamps the resulting tip between
+// GasTipCap and MaxPriorityFeePerGas, and derives the fee cap from the latest
+// base fee multiplied by BaseFeeMultiplier.
+func (p *feeHistoryGasPricer) SuggestedFees(ctx context.Context) (*big.Int, *big.Int, error) {
+	history, err := p.client.FeeHistory(ctx, p.cfg.GasPriceOracle.FeeHistoryBlockCount, nil, []float64{p.cfg.GasPriceOracle.RewardPercentile})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get fee history: %w", err)
+	}
+	if len(history.Reward) == 0 || len(history.BaseFee) == 0 {
+		return nil, nil, fmt.Errorf("fee history returned no samples")
+	}
+
+	tipCap := new(big.Int)
+	for _, reward := range history.Reward {
+		tipCap.Add(tipCap, reward[0])
+	}
+	tipCap.Div(tipCap, big.NewInt(int64(len(history.Reward))))
+
+	minTip := new(big.Int).SetUint64(p.cfg.GasTipCap)
+	if tipCap.Cmp(minTip) < 0 {
+		tipCap = minTip
+	}
+	if maxTip := p.cfg.MaxPriorityFeePerGas; maxTip != 0 && tipCap.Cmp(new(big.Int).SetUint64(maxTip)) > 0 {
+		tipCap = new(big.Int).SetUint64(maxTip)
+	}
+
+	baseFee := history.BaseFee[len(history.BaseFee)-1]
+	feeCap := mulFloat(baseFee, p.cfg.BaseFeeMultiplier)
+	feeCap.Add(feeCap, tipCap)
+	if maxFee := p.cfg.MaxFeePerGas; maxFee != 0 && feeCap.Cmp(new(big.Int).SetUint64(maxFee)) > 0 {
+		feeCap = new(big.Int).SetUint64(maxFee)
+	}
+
+	return tipCap, feeCap, nil
+}
+
+// Reprice bumps gasTipCap and gasFeeCap by RepriceBumpPercentage, falling back
+// to the minimum bump the node's txpool requires for a replacement to be accepted.
+func (p *feeHistoryGasPricer) Reprice(gasTipCap *big.Int, gasFeeCap *big.Int) (*big.Int, *big.Int) {
+	bump := p.cfg.GasPriceOracle.RepriceBumpPercentage
+	if bump < minRPCBumpPercentage {
+		bump = minRPCBumpPercentage
+	}
+	return bumpByPercentage(gasTipCap, bump), bumpByPercentage(gasFeeCap, bump)
+}
+
+// bumpByPercentage increases value by percentage percent, truncating the
+// result, and adds one when truncation leaves it equal to value, so the
+// result always clears a strict "greater than" replacement check.
+func bumpByPercentage(value *big.Int, percentage uint64) *big.Int {
+	const hundred = 100
+	bumped := new(big.Int).Mul(value, big.NewInt(int64(hundred+percentage)))
+	bumped.Div(bumped, big.NewInt(hundred))
+	if bumped.Cmp(value) == 0 {
+		bumped.Add(bumped, big.NewInt(1))
+	}
+	return bumped
+}
+
+// mulFloat multiplies value by factor, used to apply BaseFeeMultiplier to a base fee.
+func mulFloat(value *big.Int, factor float64) *big.Int {
+	result := new(big.Float).Mul(new(big.Float).SetInt(value), big.NewFloat(factor))
+	i, _ := result.Int(nil)
+	return i
+}
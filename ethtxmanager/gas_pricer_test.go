@@ -0,0 +1,113 @@
+package ethtxmanager
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum"
+)
+
+// fakeFeeHistoryClient is an in-memory feeHistoryClient used to drive
+// feeHistoryGasPricer tests without a real L1 node.
+type fakeFeeHistoryClient struct {
+	rewards []*big.Int
+	baseFee int64
+}
+
+func (f *fakeFeeHistoryClient) FeeHistory(_ context.Context, _ uint64, _ *big.Int, _ []float64) (*ethereum.FeeHistory, error) {
+	reward := make([][]*big.Int, len(f.rewards))
+	for i, r := range f.rewards {
+		reward[i] = []*big.Int{r}
+	}
+	return &ethereum.FeeHistory{
+		Reward:  reward,
+		BaseFee: []*big.Int{big.NewInt(f.baseFee)},
+	}, nil
+}
+
+func TestSuggestedFeesDerivesFeeCapFromBaseFee(t *testing.T) {
+	client := &fakeFeeHistoryClient{rewards: []*big.Int{big.NewInt(1), big.NewInt(3)}, baseFee: 100}
+	cfg := Config{BaseFeeMultiplier: 2}
+	p := NewFeeHistoryGasPricer(client, cfg)
+
+	tipCap, feeCap, err := p.SuggestedFees(context.Background())
+	if err != nil {
+		t.Fatalf("SuggestedFees failed: %v", err)
+	}
+	if want := big.NewInt(2); tipCap.Cmp(want) != 0 {
+		t.Errorf("tipCap = %s, want %s (average of the sampled rewards)", tipCap, want)
+	}
+	if want := big.NewInt(202); feeCap.Cmp(want) != 0 {
+		t.Errorf("feeCap = %s, want %s (base fee * BaseFeeMultiplier + tipCap)", feeCap, want)
+	}
+}
+
+func TestSuggestedGasPriceCoversTheBaseFee(t *testing.T) {
+	// A calm network with near-zero reward samples must still produce a
+	// legacy gas price that covers the base fee, or the tx is rejected/never
+	// mined - SuggestedGasPrice must not just return the bare tip.
+	client := &fakeFeeHistoryClient{rewards: []*big.Int{big.NewInt(0)}, baseFee: 100}
+	cfg := Config{BaseFeeMultiplier: 2}
+	p := NewFeeHistoryGasPricer(client, cfg)
+
+	gasPrice, err := p.SuggestedGasPrice(context.Background())
+	if err != nil {
+		t.Fatalf("SuggestedGasPrice failed: %v", err)
+	}
+	if want := big.NewInt(200); gasPrice.Cmp(want) < 0 {
+		t.Errorf("SuggestedGasPrice = %s, want at least %s (base fee * BaseFeeMultiplier)", gasPrice, want)
+	}
+}
+
+func TestBumpByPercentage(t *testing.T) {
+	tests := map[string]struct {
+		value      int64
+		percentage uint64
+		want       int64
+	}{
+		"10% bump of 100 is exactly 110":                        {value: 100, percentage: 10, want: 110},
+		"bump that truncates to no change is rounded up by one": {value: 1, percentage: 10, want: 2},
+		"0% bump still increases by one":                        {value: 100, percentage: 0, want: 101},
+		"zero value is still bumped by at least one":            {value: 0, percentage: 50, want: 1},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := bumpByPercentage(big.NewInt(tt.value), tt.percentage)
+			if got.Cmp(big.NewInt(tt.want)) != 0 {
+				t.Errorf("bumpByPercentage(%d, %d) = %s, want %d", tt.value, tt.percentage, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBumpByPercentageAlwaysStrictlyIncreases(t *testing.T) {
+	for _, value := range []int64{0, 1, 2, 99, 100, 1000} {
+		got := bumpByPercentage(big.NewInt(value), 10)
+		if got.Cmp(big.NewInt(value)) <= 0 {
+			t.Errorf("bumpByPercentage(%d, 10) = %s, want strictly greater than %d", value, got, value)
+		}
+	}
+}
+
+func TestMulFloat(t *testing.T) {
+	tests := map[string]struct {
+		value  int64
+		factor float64
+		want   int64
+	}{
+		"multiplier of 2 doubles the base fee": {value: 100, factor: 2, want: 200},
+		"multiplier of 1 is a no-op":           {value: 100, factor: 1, want: 100},
+		"fractional multiplier scales":         {value: 100, factor: 1.5, want: 150},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := mulFloat(big.NewInt(tt.value), tt.factor)
+			if got.Cmp(big.NewInt(tt.want)) != 0 {
+				t.Errorf("mulFloat(%d, %v) = %s, want %d", tt.value, tt.factor, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package ethtxmanager
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-ethtx-manager/config/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// registerGaugeVec registers a GaugeVec against the default Prometheus
+// registerer, returning the already-registered collector instead of
+// panicking when called more than once in the same process (e.g. a second
+// NonceManager in tests or a multi-chain deployment).
+func registerGaugeVec(opts prometheus.GaugeOpts, labels []string) *prometheus.GaugeVec {
+	gv := prometheus.NewGaugeVec(opts, labels)
+	if err := prometheus.DefaultRegisterer.Register(gv); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return are.ExistingCollector.(*prometheus.GaugeVec)
+		}
+	}
+	return gv
+}
+
+// DispatchStrategy selects how new txs are load-balanced across the
+// configured signers.
+type DispatchStrategy string
+
+const (
+	// DispatchRoundRobin cycles through signers in order.
+	DispatchRoundRobin DispatchStrategy = "round-robin"
+	// DispatchLeastPending picks the signer with the fewest unconfirmed txs.
+	DispatchLeastPending DispatchStrategy = "least-pending"
+	// DispatchAffinity picks the signer explicitly mapped to a caller-provided tag.
+	DispatchAffinity DispatchStrategy = "affinity"
+)
+
+// DispatchConfig configures the NonceManager's signer selection under
+// [EthTxManager.Dispatch].
+type DispatchConfig struct {
+	// Strategy selects how Add calls are load-balanced across signers
+	// (DispatchRoundRobin, DispatchLeastPending or DispatchAffinity).
+	Strategy DispatchStrategy `mapstructure:"Strategy"`
+	// Affinity maps a caller-provided tag to the signer address that should
+	// handle it, only used when Strategy is DispatchAffinity.
+	Affinity map[string]common.Address `mapstructure:"Affinity"`
+	// StalenessTimeout is how long a signer's oldest unconfirmed nonce must
+	// sit pending, with no on-chain progress, before StaleNonce reports it as
+	// a dropped tx that FillGap should unstick.
+	StalenessTimeout types.Duration `mapstructure:"StalenessTimeout"`
+}
+
+// nonceClient is the subset of an ethereum client needed to read the
+// on-chain nonce and send a self-transfer to fill a gap, satisfied by
+// etherman.Client.
+type nonceClient interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	SendSelfTransfer(ctx context.Context, address common.Address, nonce uint64) (common.Hash, error)
+}
+
+// signerNonces tracks the nonce bookkeeping for a single signer.
+type signerNonces struct {
+	confirmed uint64 // highest nonce known to be mined, plus one
+
+	reserved uint64 // next nonce to hand out
+
+	// pendingSince is when confirmed first fell behind reserved, i.e. since
+	// when the signer's oldest unconfirmed nonce (confirmed itself, since
+	// nonces are strictly sequential on-chain) has been outstanding. It's the
+	// zero Time whenever nothing is pending.
+	pendingSince time.Time
+}
+
+// pending returns how many txs are reserved but not yet confirmed.
+func (s *signerNonces) pending() uint64 {
+	if s.reserved <= s.confirmed {
+		return 0
+	}
+	return s.reserved - s.confirmed
+}
+
+// NonceManager tracks the on-chain and locally-reserved nonce per configured
+// signer independently, and load-balances new Add calls across them so a
+// single account no longer serializes every tx the manager sends.
+type NonceManager struct {
+	cfg       DispatchConfig
+	client    nonceClient
+	addresses []common.Address
+
+	mu       sync.Mutex
+	nonces   map[common.Address]*signerNonces
+	rrCursor int
+
+	pendingGauge   *prometheus.GaugeVec
+	confirmedGauge *prometheus.GaugeVec
+}
+
+// NewNonceManager creates a NonceManager for addresses, seeding each
+// signer's nonce bookkeeping from the on-chain pending nonce.
+func NewNonceManager(ctx context.Context, cfg DispatchConfig, client nonceClient, addresses []common.Address) (*NonceManager, error) {
+	m := &NonceManager{
+		cfg:       cfg,
+		client:    client,
+		addresses: addresses,
+		nonces:    make(map[common.Address]*signerNonces, len(addresses)),
+		pendingGauge: registerGaugeVec(prometheus.GaugeOpts{
+			Name: "ethtxmanager_pending_nonce",
+			Help: "Next nonce reserved locally for a signer, not yet confirmed on-chain.",
+		}, []string{"addr"}),
+		confirmedGauge: registerGaugeVec(prometheus.GaugeOpts{
+			Name: "ethtxmanager_confirmed_nonce",
+			Help: "Highest nonce confirmed on-chain for a signer, plus one.",
+		}, []string{"addr"}),
+	}
+
+	for _, address := range addresses {
+		nonce, err := client.PendingNonceAt(ctx, address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read pending nonce for %s: %w", address, err)
+		}
+		m.nonces[address] = &signerNonces{confirmed: nonce, reserved: nonce}
+		m.confirmedGauge.WithLabelValues(address.Hex()).Set(float64(nonce))
+		m.pendingGauge.WithLabelValues(address.Hex()).Set(float64(nonce))
+	}
+
+	return m, nil
+}
+
+// SelectAndReserve picks a signer according to DispatchConfig.Strategy and
+// reserves its next nonce in a single locked step, so two concurrent Add
+// calls can never be handed the same (signer, nonce) pair.
+func (m *NonceManager) SelectAndReserve(tag string) (common.Address, uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	address, err := m.selectSignerLocked(tag)
+	if err != nil {
+		return common.Address{}, 0, err
+	}
+
+	state := m.nonces[address]
+	if state.reserved == state.confirmed {
+		state.pendingSince = time.Now()
+	}
+	nonce := state.reserved
+	state.reserved++
+	m.pendingGauge.WithLabelValues(address.Hex()).Set(float64(state.reserved))
+
+	return address, nonce, nil
+}
+
+// selectSignerLocked implements DispatchConfig.Strategy, the caller must
+// hold m.mu.
+func (m *NonceManager) selectSignerLocked(tag string) (common.Address, error) {
+	if len(m.addresses) == 0 {
+		return common.Address{}, fmt.Errorf("no signers configured for dispatch")
+	}
+
+	switch m.cfg.Strategy {
+	case DispatchAffinity:
+		address, ok := m.cfg.Affinity[tag]
+		if !ok {
+			return common.Address{}, fmt.Errorf("no signer affinity configured for tag %q", tag)
+		}
+		return address, nil
+
+	case DispatchLeastPending:
+		best := m.addresses[0]
+		bestPending := m.nonces[best].pending()
+		for _, address := range m.addresses[1:] {
+			if p := m.nonces[address].pending(); p < bestPending {
+				best, bestPending = address, p
+			}
+		}
+		return best, nil
+
+	case DispatchRoundRobin, "":
+		address := m.addresses[m.rrCursor%len(m.addresses)]
+		m.rrCursor++
+		return address, nil
+
+	default:
+		return common.Address{}, fmt.Errorf("unknown dispatch strategy %q", m.cfg.Strategy)
+	}
+}
+
+// ConfirmNonce records that nonce has been mined for address, advancing its
+// confirmed watermark. Ethereum nonces are strictly sequential on-chain, so
+// nonce is expected to equal the signer's previously confirmed watermark
+// (the lowest nonce still outstanding) — a higher nonce being confirmed first
+// isn't a "gap" to backfill, it means confirmations for the lower nonces
+// simply haven't been observed yet and will land once their receipts do.
+func (m *NonceManager) ConfirmNonce(address common.Address, nonce uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, ok := m.nonces[address]
+	if !ok || nonce < state.confirmed {
+		return
+	}
+
+	state.confirmed = nonce + 1
+	if state.reserved < state.confirmed {
+		state.reserved = state.confirmed
+	}
+	if state.confirmed == state.reserved {
+		state.pendingSince = time.Time{}
+	} else {
+		// Nonces above the one we just confirmed may already be mined too
+		// (their receipts just haven't been observed yet), so restart the
+		// staleness clock for whatever is left outstanding instead of
+		// treating it as stuck since the original reservation time.
+		state.pendingSince = time.Now()
+	}
+
+	m.confirmedGauge.WithLabelValues(address.Hex()).Set(float64(state.confirmed))
+	m.pendingGauge.WithLabelValues(address.Hex()).Set(float64(state.reserved))
+}
+
+// StaleNonce reports address's oldest unconfirmed nonce (always equal to its
+// confirmed watermark, since nonces are consumed in order) when it has been
+// reserved for longer than DispatchConfig.StalenessTimeout and the on-chain
+// pending nonce confirms it still hasn't progressed — the real signal that
+// the tx for it was dropped, as opposed to merely waiting on a receipt we
+// haven't polled yet. ok is false when nothing is stuck.
+func (m *NonceManager) StaleNonce(ctx context.Context, address common.Address) (nonce uint64, ok bool, err error) {
+	if m.cfg.StalenessTimeout.Duration <= 0 {
+		return 0, false, nil
+	}
+
+	m.mu.Lock()
+	state, tracked := m.nonces[address]
+	if !tracked || state.pendingSince.IsZero() || time.Since(state.pendingSince) < m.cfg.StalenessTimeout.Duration {
+		m.mu.Unlock()
+		return 0, false, nil
+	}
+	stuckNonce := state.confirmed
+	m.mu.Unlock()
+
+	onChainNonce, err := m.client.PendingNonceAt(ctx, address)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read on-chain pending nonce for %s: %w", address, err)
+	}
+	if onChainNonce > stuckNonce {
+		// The chain already moved past stuckNonce, so it was mined (or
+		// replaced); our watermark just hasn't caught up via ConfirmNonce yet.
+		return 0, false, nil
+	}
+
+	return stuckNonce, true, nil
+}
+
+// FillGap issues a zero-value self-transfer at nonce for address, used to
+// unstick a signer after StaleNonce reports a dropped tx.
+func (m *NonceManager) FillGap(ctx context.Context, address common.Address, nonce uint64) (common.Hash, error) {
+	return m.client.SendSelfTransfer(ctx, address, nonce)
+}
@@ -0,0 +1,176 @@
+package ethtxmanager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-ethtx-manager/config/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// fakeNonceClient is an in-memory nonceClient used to drive NonceManager
+// tests without a real L1 node.
+type fakeNonceClient struct {
+	pendingNonce  map[common.Address]uint64
+	selfTransfers []uint64
+}
+
+func (f *fakeNonceClient) PendingNonceAt(_ context.Context, account common.Address) (uint64, error) {
+	return f.pendingNonce[account], nil
+}
+
+func (f *fakeNonceClient) SendSelfTransfer(_ context.Context, _ common.Address, nonce uint64) (common.Hash, error) {
+	f.selfTransfers = append(f.selfTransfers, nonce)
+	return common.Hash{}, nil
+}
+
+func TestConfirmNonceAdvancesWatermarkSequentially(t *testing.T) {
+	address := common.HexToAddress("0xaaaa")
+	client := &fakeNonceClient{pendingNonce: map[common.Address]uint64{address: 0}}
+	m, err := NewNonceManager(context.Background(), DispatchConfig{}, client, []common.Address{address})
+	if err != nil {
+		t.Fatalf("NewNonceManager failed: %v", err)
+	}
+
+	if _, _, err := m.SelectAndReserve(""); err != nil {
+		t.Fatalf("SelectAndReserve failed: %v", err)
+	}
+	if _, _, err := m.SelectAndReserve(""); err != nil {
+		t.Fatalf("SelectAndReserve failed: %v", err)
+	}
+
+	// Confirming nonce 0 must only advance the watermark to 1 (the next
+	// outstanding nonce), never treat anything below it as a "gap" to fill -
+	// on-chain nonces are strictly sequential, so there is nothing below a
+	// confirmed nonce left to backfill.
+	m.ConfirmNonce(address, 0)
+
+	state := m.nonces[address]
+	if state.confirmed != 1 {
+		t.Errorf("confirmed = %d, want 1", state.confirmed)
+	}
+	if state.reserved != 2 {
+		t.Errorf("reserved = %d, want 2 (unaffected by confirming nonce 0)", state.reserved)
+	}
+}
+
+func TestConfirmNonceIgnoresAlreadyConfirmed(t *testing.T) {
+	address := common.HexToAddress("0xaaaa")
+	client := &fakeNonceClient{pendingNonce: map[common.Address]uint64{address: 0}}
+	m, err := NewNonceManager(context.Background(), DispatchConfig{}, client, []common.Address{address})
+	if err != nil {
+		t.Fatalf("NewNonceManager failed: %v", err)
+	}
+
+	if _, _, err := m.SelectAndReserve(""); err != nil {
+		t.Fatalf("SelectAndReserve failed: %v", err)
+	}
+	m.ConfirmNonce(address, 0)
+	m.ConfirmNonce(address, 0) // replayed/duplicate confirmation must be a no-op
+
+	if state := m.nonces[address]; state.confirmed != 1 {
+		t.Errorf("confirmed = %d, want 1 after a duplicate confirmation", state.confirmed)
+	}
+}
+
+func TestStaleNonceDisabledWhenTimeoutIsZero(t *testing.T) {
+	address := common.HexToAddress("0xaaaa")
+	client := &fakeNonceClient{pendingNonce: map[common.Address]uint64{address: 0}}
+	m, err := NewNonceManager(context.Background(), DispatchConfig{}, client, []common.Address{address})
+	if err != nil {
+		t.Fatalf("NewNonceManager failed: %v", err)
+	}
+	if _, _, err := m.SelectAndReserve(""); err != nil {
+		t.Fatalf("SelectAndReserve failed: %v", err)
+	}
+
+	_, stale, err := m.StaleNonce(context.Background(), address)
+	if err != nil {
+		t.Fatalf("StaleNonce failed: %v", err)
+	}
+	if stale {
+		t.Error("expected StaleNonce to report nothing stale when StalenessTimeout is 0 (disabled)")
+	}
+}
+
+func TestStaleNonceReportsOutstandingNonceAfterTimeout(t *testing.T) {
+	address := common.HexToAddress("0xaaaa")
+	client := &fakeNonceClient{pendingNonce: map[common.Address]uint64{address: 0}}
+	cfg := DispatchConfig{StalenessTimeout: types.Duration{Duration: time.Millisecond}}
+	m, err := NewNonceManager(context.Background(), cfg, client, []common.Address{address})
+	if err != nil {
+		t.Fatalf("NewNonceManager failed: %v", err)
+	}
+	if _, _, err := m.SelectAndReserve(""); err != nil {
+		t.Fatalf("SelectAndReserve failed: %v", err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	nonce, stale, err := m.StaleNonce(context.Background(), address)
+	if err != nil {
+		t.Fatalf("StaleNonce failed: %v", err)
+	}
+	if !stale {
+		t.Fatal("expected StaleNonce to report the outstanding nonce as stale after the timeout elapsed")
+	}
+	if nonce != 0 {
+		t.Errorf("stale nonce = %d, want 0", nonce)
+	}
+}
+
+func TestStaleNonceNotReportedWhenChainAlreadyProgressed(t *testing.T) {
+	address := common.HexToAddress("0xaaaa")
+	client := &fakeNonceClient{pendingNonce: map[common.Address]uint64{address: 0}}
+	cfg := DispatchConfig{StalenessTimeout: types.Duration{Duration: time.Millisecond}}
+	m, err := NewNonceManager(context.Background(), cfg, client, []common.Address{address})
+	if err != nil {
+		t.Fatalf("NewNonceManager failed: %v", err)
+	}
+	if _, _, err := m.SelectAndReserve(""); err != nil {
+		t.Fatalf("SelectAndReserve failed: %v", err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	// The on-chain pending nonce has already moved past the nonce we think is
+	// stuck, meaning it was actually mined and our watermark just hasn't
+	// caught up via ConfirmNonce yet - StaleNonce must not call it stale.
+	client.pendingNonce[address] = 1
+
+	_, stale, err := m.StaleNonce(context.Background(), address)
+	if err != nil {
+		t.Fatalf("StaleNonce failed: %v", err)
+	}
+	if stale {
+		t.Error("expected StaleNonce to not report a nonce the chain has already progressed past")
+	}
+}
+
+func TestSelectAndReserveLeastPendingPicksFewestOutstanding(t *testing.T) {
+	a := common.HexToAddress("0xaaaa")
+	b := common.HexToAddress("0xbbbb")
+	client := &fakeNonceClient{pendingNonce: map[common.Address]uint64{a: 0, b: 0}}
+	cfg := DispatchConfig{Strategy: DispatchLeastPending}
+	m, err := NewNonceManager(context.Background(), cfg, client, []common.Address{a, b})
+	if err != nil {
+		t.Fatalf("NewNonceManager failed: %v", err)
+	}
+
+	// selectSignerLocked recomputes pending() before every pick. On the
+	// first call both are tied at 0 pending, so it keeps the first address
+	// in m.addresses (`a`), leaving `a` with one more outstanding
+	// reservation than `b`. The second call must then pick `b`, the signer
+	// with fewer outstanding reservations, not `a` again.
+	want := []common.Address{a, b}
+	for i, w := range want {
+		addr, _, err := m.SelectAndReserve("")
+		if err != nil {
+			t.Fatalf("SelectAndReserve failed: %v", err)
+		}
+		if addr != w {
+			t.Fatalf("reservation %d landed on %s, want %s", i, addr, w)
+		}
+	}
+}
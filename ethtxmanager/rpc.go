@@ -0,0 +1,495 @@
+package ethtxmanager
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-ethtx-manager/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/metadata"
+)
+
+// hexBigInt unmarshals a JSON-RPC "0x..."-quantity into a *big.Int.
+type hexBigInt struct {
+	value *big.Int
+}
+
+func (h *hexBigInt) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	v, err := hexutil.DecodeBig(s)
+	if err != nil {
+		return fmt.Errorf("invalid hex quantity %q: %w", s, err)
+	}
+	h.value = v
+	return nil
+}
+
+// toBigInt returns the decoded value, or nil if h is nil (the field was omitted).
+func (h *hexBigInt) toBigInt() *big.Int {
+	if h == nil {
+		return nil
+	}
+	return h.value
+}
+
+// RPCConfig configures the JSON-RPC admin endpoint exposed under
+// [EthTxManager.RPC].
+type RPCConfig struct {
+	// Host is the address the JSON-RPC HTTP server listens on.
+	Host string `mapstructure:"Host"`
+	// Port is the port the JSON-RPC HTTP server listens on.
+	Port int `mapstructure:"Port"`
+	// EnableAuth requires callers to present a bearer token matching AuthToken.
+	EnableAuth bool `mapstructure:"EnableAuth"`
+	// AuthToken is the bearer token required when EnableAuth is true.
+	AuthToken string `mapstructure:"AuthToken"`
+	// RateLimitPerSecond caps how many requests per second a single method
+	// accepts across all callers, default value is 0, which means no limit.
+	RateLimitPerSecond float64 `mapstructure:"RateLimitPerSecond"`
+	// EnableGRPC additionally exposes the same JSON-RPC methods over gRPC on
+	// GRPCPort, for callers that prefer it over HTTP.
+	EnableGRPC bool `mapstructure:"EnableGRPC"`
+	// GRPCPort is the port the gRPC server listens on, used when EnableGRPC is true.
+	GRPCPort int `mapstructure:"GRPCPort"`
+}
+
+// rpcBackend is the subset of the ethtxmanager Client the RPC server needs,
+// kept narrow so the server can be tested and wired independently of the
+// concrete manager implementation.
+type rpcBackend interface {
+	Add(ctx context.Context, to *common.Address, value *big.Int, data []byte, blobs ...[]byte) (common.Hash, error)
+	Result(ctx context.Context, hash common.Hash) (interface{}, error)
+	ResultsByStatus(ctx context.Context, statuses []string) ([]interface{}, error)
+	Remove(ctx context.Context, hash common.Hash) error
+	SetGasPrice(gasPrice *big.Int)
+	SetMaxGasPrice(maxGasPrice *big.Int)
+}
+
+// rpcRequest is a JSON-RPC 2.0 request.
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+// rpcResponse is a JSON-RPC 2.0 response.
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is a JSON-RPC 2.0 error object.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+const (
+	rpcMethodSetGasPrice     = "ethtxmanager_setGasPrice"
+	rpcMethodSetMaxGasPrice  = "ethtxmanager_setMaxGasPrice"
+	rpcMethodAdd             = "ethtxmanager_add"
+	rpcMethodResult          = "ethtxmanager_result"
+	rpcMethodResultsByStatus = "ethtxmanager_resultsByStatus"
+	rpcMethodRemove          = "ethtxmanager_remove"
+
+	rpcErrCodeParse         = -32700
+	rpcErrCodeInvalidParams = -32602
+	rpcErrCodeInternal      = -32603
+	rpcErrCodeUnauthorized  = -32000
+	rpcErrCodeRateLimited   = -32001
+)
+
+// adminMethods lists the methods that always require a valid bearer token,
+// even when EnableAuth is false, mirroring the admin-only surface of go-ethereum's miner.setGasPrice.
+var adminMethods = map[string]bool{
+	rpcMethodSetGasPrice:    true,
+	rpcMethodSetMaxGasPrice: true,
+}
+
+// knownMethods lists every method dispatch can serve, checked before a
+// request consults authorize/the rate limiter so an unauthenticated caller
+// can't grow the limiter's per-method bookkeeping with garbage method names.
+var knownMethods = map[string]bool{
+	rpcMethodAdd:             true,
+	rpcMethodResult:          true,
+	rpcMethodResultsByStatus: true,
+	rpcMethodRemove:          true,
+	rpcMethodSetGasPrice:     true,
+	rpcMethodSetMaxGasPrice:  true,
+}
+
+// rpcMetrics holds the Prometheus counters exposed by the RPC server.
+type rpcMetrics struct {
+	callsTotal  *prometheus.CounterVec
+	errorsTotal *prometheus.CounterVec
+}
+
+// newRPCMetrics registers and returns the RPC server's Prometheus counters.
+func newRPCMetrics() *rpcMetrics {
+	return &rpcMetrics{
+		callsTotal: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name: "ethtxmanager_rpc_calls_total",
+			Help: "Number of JSON-RPC calls received, labeled by method.",
+		}, []string{"method"}),
+		errorsTotal: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name: "ethtxmanager_rpc_errors_total",
+			Help: "Number of JSON-RPC calls that returned an error, labeled by method.",
+		}, []string{"method"}),
+	}
+}
+
+// rateLimiter is a simple per-method token bucket refilled at
+// RPCConfig.RateLimitPerSecond, used to protect the manager from a noisy
+// sequencer/aggregator process.
+type rateLimiter struct {
+	mu         sync.Mutex
+	ratePerSec float64
+	tokens     map[string]float64
+	lastRefill map[string]time.Time
+}
+
+func newRateLimiter(ratePerSec float64) *rateLimiter {
+	return &rateLimiter{
+		ratePerSec: ratePerSec,
+		tokens:     make(map[string]float64),
+		lastRefill: make(map[string]time.Time),
+	}
+}
+
+// Allow reports whether method is allowed to proceed now, consuming a token
+// if so. A non-positive ratePerSec disables rate limiting entirely.
+func (r *rateLimiter) Allow(method string) bool {
+	if r.ratePerSec <= 0 {
+		return true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	last, ok := r.lastRefill[method]
+	if !ok {
+		last = now
+		r.tokens[method] = r.ratePerSec
+	}
+	elapsed := now.Sub(last).Seconds()
+	r.tokens[method] = minFloat(r.ratePerSec, r.tokens[method]+elapsed*r.ratePerSec)
+	r.lastRefill[method] = now
+
+	if r.tokens[method] < 1 {
+		return false
+	}
+	r.tokens[method]--
+	return true
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// RPCServer exposes the ethtxmanager Client over JSON-RPC, so other
+// sequencer/aggregator processes can share a single instance.
+type RPCServer struct {
+	cfg     RPCConfig
+	backend rpcBackend
+	metrics *rpcMetrics
+	limiter *rateLimiter
+	server  *http.Server
+}
+
+// NewRPCServer creates an RPCServer that dispatches calls to backend.
+func NewRPCServer(cfg RPCConfig, backend rpcBackend) *RPCServer {
+	return &RPCServer{
+		cfg:     cfg,
+		backend: backend,
+		metrics: newRPCMetrics(),
+		limiter: newRateLimiter(cfg.RateLimitPerSecond),
+	}
+}
+
+// Start listens on cfg.Host:cfg.Port and serves JSON-RPC requests until ctx
+// is cancelled.
+func (s *RPCServer) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handle)
+
+	address := fmt.Sprintf("%s:%d", s.cfg.Host, s.cfg.Port)
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", address, err)
+	}
+
+	const readHeaderTimeout = 10 * time.Second
+	s.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = s.server.Close()
+	}()
+
+	log.Infof("ethtxmanager RPC server listening on %s", address)
+	if err := s.server.Serve(lis); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("ethtxmanager RPC server stopped unexpectedly: %w", err)
+	}
+	return nil
+}
+
+func (s *RPCServer) handle(w http.ResponseWriter, r *http.Request) {
+	var req rpcRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeRPCError(w, nil, rpcErrCodeParse, "failed to parse request")
+		return
+	}
+
+	res := s.processRequest(r.Context(), req, bearerToken(r.Header.Get("Authorization")))
+	writeRPCResponse(w, res)
+}
+
+// processRequest runs the shared method-validation → authorize → rate-limit
+// → dispatch pipeline for req, so the HTTP and gRPC surfaces share one
+// implementation instead of drifting apart. The method is checked against
+// knownMethods before authorize/the rate limiter are ever consulted, so an
+// unauthenticated caller can't grow the limiter's per-method state with
+// method names that will never dispatch to anything.
+func (s *RPCServer) processRequest(ctx context.Context, req rpcRequest, token string) rpcResponse {
+	if !knownMethods[req.Method] {
+		return rpcResponse{JSONRPC: "2.0", ID: req.ID, Error: &rpcError{Code: rpcErrCodeInvalidParams, Message: "method not found: " + req.Method}}
+	}
+
+	if !s.authorize(req.Method, token) {
+		s.metrics.callsTotal.WithLabelValues(req.Method).Inc()
+		s.metrics.errorsTotal.WithLabelValues(req.Method).Inc()
+		return rpcResponse{JSONRPC: "2.0", ID: req.ID, Error: &rpcError{Code: rpcErrCodeUnauthorized, Message: "missing or invalid bearer token"}}
+	}
+
+	if !s.limiter.Allow(req.Method) {
+		s.metrics.callsTotal.WithLabelValues(req.Method).Inc()
+		s.metrics.errorsTotal.WithLabelValues(req.Method).Inc()
+		return rpcResponse{JSONRPC: "2.0", ID: req.ID, Error: &rpcError{Code: rpcErrCodeRateLimited, Message: "rate limit exceeded for method " + req.Method}}
+	}
+
+	s.metrics.callsTotal.WithLabelValues(req.Method).Inc()
+	result, err := s.dispatch(ctx, req)
+	if err != nil {
+		s.metrics.errorsTotal.WithLabelValues(req.Method).Inc()
+		return rpcResponse{JSONRPC: "2.0", ID: req.ID, Error: &rpcError{Code: rpcErrCodeInternal, Message: err.Error()}}
+	}
+	return rpcResponse{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+// bearerToken extracts the token from an HTTP "Authorization: Bearer <token>" header.
+func bearerToken(header string) string {
+	return strings.TrimPrefix(header, "Bearer ")
+}
+
+// authorize checks token for method when EnableAuth is set, or when method is
+// admin-only regardless of the global EnableAuth setting, using a
+// constant-time comparison so a caller can't infer the configured AuthToken
+// from response timing.
+func (s *RPCServer) authorize(method, token string) bool {
+	if !s.cfg.EnableAuth && !adminMethods[method] {
+		return true
+	}
+	if token == "" || s.cfg.AuthToken == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.cfg.AuthToken)) == 1
+}
+
+func (s *RPCServer) dispatch(ctx context.Context, req rpcRequest) (interface{}, error) {
+	switch req.Method {
+	case rpcMethodAdd:
+		var params struct {
+			To    *common.Address `json:"to"`
+			Value *hexBigInt      `json:"value"`
+			Data  string          `json:"data"`
+			Blobs []string        `json:"blobs"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, fmt.Errorf("invalid params: %w", err)
+		}
+		blobs := make([][]byte, len(params.Blobs))
+		for i, blob := range params.Blobs {
+			blobs[i] = common.FromHex(blob)
+		}
+		hash, err := s.backend.Add(ctx, params.To, params.Value.toBigInt(), common.FromHex(params.Data), blobs...)
+		if err != nil {
+			return nil, err
+		}
+		return hash, nil
+
+	case rpcMethodResult:
+		var params struct {
+			Hash common.Hash `json:"hash"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, fmt.Errorf("invalid params: %w", err)
+		}
+		return s.backend.Result(ctx, params.Hash)
+
+	case rpcMethodResultsByStatus:
+		var params struct {
+			Statuses []string `json:"statuses"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, fmt.Errorf("invalid params: %w", err)
+		}
+		return s.backend.ResultsByStatus(ctx, params.Statuses)
+
+	case rpcMethodRemove:
+		var params struct {
+			Hash common.Hash `json:"hash"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, fmt.Errorf("invalid params: %w", err)
+		}
+		return nil, s.backend.Remove(ctx, params.Hash)
+
+	case rpcMethodSetGasPrice:
+		var params struct {
+			GasPrice *hexBigInt `json:"gasPrice"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, fmt.Errorf("invalid params: %w", err)
+		}
+		s.backend.SetGasPrice(params.GasPrice.toBigInt())
+		return nil, nil
+
+	case rpcMethodSetMaxGasPrice:
+		var params struct {
+			MaxGasPrice *hexBigInt `json:"maxGasPrice"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, fmt.Errorf("invalid params: %w", err)
+		}
+		s.backend.SetMaxGasPrice(params.MaxGasPrice.toBigInt())
+		return nil, nil
+
+	default:
+		return nil, fmt.Errorf("method not found: %s", req.Method)
+	}
+}
+
+func writeRPCResult(w http.ResponseWriter, id json.RawMessage, result interface{}) {
+	writeRPCResponse(w, rpcResponse{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+func writeRPCError(w http.ResponseWriter, id json.RawMessage, code int, message string) {
+	writeRPCResponse(w, rpcResponse{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: message}})
+}
+
+func writeRPCResponse(w http.ResponseWriter, res rpcResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		log.Errorf("failed to encode RPC response: %v", err)
+	}
+}
+
+// grpcCodec lets the optional gRPC surface exchange the same rpcRequest/
+// rpcResponse structs the HTTP surface uses, so this admin-only API doesn't
+// need a generated protobuf codec of its own.
+type grpcCodec struct{}
+
+func (grpcCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (grpcCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (grpcCodec) Name() string                               { return "ethtxmanager-json" }
+
+func init() {
+	encoding.RegisterCodec(grpcCodec{})
+}
+
+// ethTxManagerGRPCServiceDesc describes the single generic "Call" RPC that
+// carries the same rpcRequest/rpcResponse envelope as the HTTP surface.
+var ethTxManagerGRPCServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ethtxmanager.EthTxManager",
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Call", Handler: ethTxManagerGRPCCallHandler},
+	},
+	Metadata: "ethtxmanager.proto",
+}
+
+func ethTxManagerGRPCCallHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	var req rpcRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(*RPCServer).processRequest(ctx, req.(rpcRequest), grpcBearerToken(ctx)), nil
+	}
+	if interceptor == nil {
+		return handler(ctx, req)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/ethtxmanager.EthTxManager/Call"}
+	return interceptor(ctx, req, info, handler)
+}
+
+// grpcBearerToken extracts the token from an incoming "authorization: Bearer
+// <token>" gRPC metadata entry, the gRPC equivalent of the HTTP surface's
+// Authorization header.
+func grpcBearerToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return ""
+	}
+	return bearerToken(values[0])
+}
+
+// StartGRPC listens on cfg.Host:cfg.GRPCPort and serves the optional gRPC
+// surface until ctx is cancelled, reusing the same authorize/rate-limit/
+// dispatch pipeline as Start. It's a no-op when EnableGRPC is false.
+func (s *RPCServer) StartGRPC(ctx context.Context) error {
+	if !s.cfg.EnableGRPC {
+		return nil
+	}
+
+	address := fmt.Sprintf("%s:%d", s.cfg.Host, s.cfg.GRPCPort)
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", address, err)
+	}
+
+	grpcServer := grpc.NewServer(grpc.ForceServerCodec(grpcCodec{}))
+	grpcServer.RegisterService(&ethTxManagerGRPCServiceDesc, s)
+
+	go func() {
+		<-ctx.Done()
+		grpcServer.GracefulStop()
+	}()
+
+	log.Infof("ethtxmanager gRPC server listening on %s", address)
+	if err := grpcServer.Serve(lis); err != nil && err != grpc.ErrServerStopped {
+		return fmt.Errorf("ethtxmanager gRPC server stopped unexpectedly: %w", err)
+	}
+	return nil
+}
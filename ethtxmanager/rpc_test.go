@@ -0,0 +1,87 @@
+package ethtxmanager
+
+import (
+	"testing"
+)
+
+func TestRateLimiterAllowsUpToBurstThenBlocks(t *testing.T) {
+	limiter := newRateLimiter(5)
+
+	for i := 0; i < 5; i++ {
+		if !limiter.Allow("ethtxmanager_add") {
+			t.Fatalf("call %d: expected Allow to succeed within the initial burst of 5 tokens", i)
+		}
+	}
+	if limiter.Allow("ethtxmanager_add") {
+		t.Error("expected Allow to block once the token bucket is exhausted")
+	}
+}
+
+func TestRateLimiterTracksMethodsIndependently(t *testing.T) {
+	limiter := newRateLimiter(1)
+
+	if !limiter.Allow("ethtxmanager_add") {
+		t.Fatal("expected the first call for ethtxmanager_add to be allowed")
+	}
+	if !limiter.Allow("ethtxmanager_remove") {
+		t.Error("expected a different method to have its own, unconsumed token bucket")
+	}
+}
+
+func TestRateLimiterDisabledWhenRateIsNonPositive(t *testing.T) {
+	limiter := newRateLimiter(0)
+	for i := 0; i < 100; i++ {
+		if !limiter.Allow("ethtxmanager_add") {
+			t.Fatalf("call %d: expected Allow to always succeed when RateLimitPerSecond is 0", i)
+		}
+	}
+}
+
+func TestAuthorizePublicMethodWithoutAuthEnabled(t *testing.T) {
+	s := &RPCServer{cfg: RPCConfig{EnableAuth: false}}
+	if !s.authorize(rpcMethodAdd, "") {
+		t.Error("expected a public method to be authorized when EnableAuth is false")
+	}
+}
+
+func TestAuthorizeAdminMethodAlwaysRequiresToken(t *testing.T) {
+	s := &RPCServer{cfg: RPCConfig{EnableAuth: false, AuthToken: "secret"}}
+	if s.authorize(rpcMethodSetGasPrice, "") {
+		t.Error("expected an admin method to require a token even when EnableAuth is false")
+	}
+	if !s.authorize(rpcMethodSetGasPrice, "secret") {
+		t.Error("expected an admin method to be authorized with the correct token")
+	}
+}
+
+func TestAuthorizeRejectsWrongToken(t *testing.T) {
+	s := &RPCServer{cfg: RPCConfig{EnableAuth: true, AuthToken: "secret"}}
+	if s.authorize(rpcMethodAdd, "wrong") {
+		t.Error("expected authorize to reject an incorrect bearer token")
+	}
+	if s.authorize(rpcMethodAdd, "") {
+		t.Error("expected authorize to reject an empty bearer token")
+	}
+}
+
+func TestProcessRequestRejectsUnknownMethodBeforeRateLimiting(t *testing.T) {
+	s := &RPCServer{
+		cfg:     RPCConfig{RateLimitPerSecond: 1},
+		metrics: newRPCMetrics(),
+		limiter: newRateLimiter(1),
+	}
+
+	// Confirm an unknown method is rejected without ever touching the
+	// limiter, so it can't grow unbounded
+	// per-method state for garbage method names from unauthenticated callers.
+	res := s.processRequest(nil, rpcRequest{Method: "not_a_real_method"}, "")
+	if res.Error == nil {
+		t.Fatal("expected an error response for an unknown method")
+	}
+	if res.Error.Code != rpcErrCodeInvalidParams {
+		t.Errorf("got error code %d, want %d", res.Error.Code, rpcErrCodeInvalidParams)
+	}
+	if _, seen := s.limiter.lastRefill["not_a_real_method"]; seen {
+		t.Error("expected the rate limiter to never observe an unknown method")
+	}
+}
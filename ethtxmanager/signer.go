@@ -0,0 +1,146 @@
+package ethtxmanager
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// signTimeout bounds how long the bind.TransactOpts.Signer callback waits on
+// a remote Signer (Web3Signer, KMS). bind.SignerFn's signature predates
+// context.Context and can't take the caller's ctx, so without this a hung or
+// slow backend would block Add - and the signer nonce it already reserved -
+// indefinitely.
+const signTimeout = 30 * time.Second
+
+// SignerType identifies a remote signing backend.
+type SignerType string
+
+const (
+	// SignerTypeWeb3Signer signs through a Web3Signer HTTP endpoint, speaking
+	// the Web3Signer JSON schema (POST /api/v1/eth1/sign/{identifier}).
+	SignerTypeWeb3Signer SignerType = "web3signer"
+	// SignerTypeAWSKMS signs through an AWS KMS asymmetric signing key.
+	SignerTypeAWSKMS SignerType = "aws-kms"
+	// SignerTypeGCPKMS signs through a GCP KMS asymmetric signing key.
+	SignerTypeGCPKMS SignerType = "gcp-kms"
+)
+
+// SignerConfig configures a single remote signing backend entry under
+// [EthTxManager.Signers], identified by the Ethereum address it signs for.
+type SignerConfig struct {
+	// Address is the Ethereum address this backend signs transactions for.
+	Address common.Address `mapstructure:"Address"`
+	// Type selects the remote signing backend (SignerTypeWeb3Signer, SignerTypeAWSKMS, SignerTypeGCPKMS).
+	Type SignerType `mapstructure:"Type"`
+	// Web3SignerURL is the base URL of the Web3Signer instance, used when Type is SignerTypeWeb3Signer.
+	Web3SignerURL string `mapstructure:"Web3SignerURL"`
+	// AWSKMSKeyID is the KMS key ID or ARN used when Type is SignerTypeAWSKMS.
+	AWSKMSKeyID string `mapstructure:"AWSKMSKeyID"`
+	// AWSKMSRegion is the AWS region of the KMS key used when Type is SignerTypeAWSKMS.
+	AWSKMSRegion string `mapstructure:"AWSKMSRegion"`
+	// GCPKMSKeyResourceName is the fully qualified KMS key version resource name
+	// (projects/*/locations/*/keyRings/*/cryptoKeys/*/cryptoKeyVersions/*) used
+	// when Type is SignerTypeGCPKMS.
+	GCPKMSKeyResourceName string `mapstructure:"GCPKMSKeyResourceName"`
+}
+
+// Signer signs transaction hashes on behalf of an Ethereum address without
+// necessarily exposing the underlying private key, implementations back
+// local keystore files as well as remote backends (Web3Signer, AWS KMS, GCP KMS).
+type Signer interface {
+	// Address returns the Ethereum address this Signer signs for.
+	Address() common.Address
+	// SignHash signs hash and returns the 65-byte [R || S || V] signature.
+	SignHash(ctx context.Context, hash [32]byte) ([]byte, error)
+}
+
+// localKeystoreSigner signs with a private key loaded from a keystore file,
+// preserving the existing etherman.LoadAuthFromKeyStore behavior.
+type localKeystoreSigner struct {
+	address common.Address
+	key     *ecdsa.PrivateKey
+}
+
+// NewLocalKeystoreSigner creates a Signer backed by an in-memory private key,
+// matching the behavior previously implemented by etherman.LoadAuthFromKeyStore.
+func NewLocalKeystoreSigner(key *ecdsa.PrivateKey) Signer {
+	return &localKeystoreSigner{
+		address: crypto.PubkeyToAddress(key.PublicKey),
+		key:     key,
+	}
+}
+
+func (s *localKeystoreSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *localKeystoreSigner) SignHash(_ context.Context, hash [32]byte) ([]byte, error) {
+	return crypto.Sign(hash[:], s.key)
+}
+
+// SignerManager resolves the Signer configured for an address and caches the
+// bind.TransactOpts derived from it, so repeated sends for the same address
+// don't need to re-resolve or re-wrap the signing callback.
+type SignerManager struct {
+	signers  map[common.Address]Signer
+	txSigner types.Signer
+
+	mu   sync.Mutex
+	opts map[common.Address]*bind.TransactOpts
+}
+
+// NewSignerManager creates a SignerManager serving the given signers for txs
+// on the network identified by chainID.
+func NewSignerManager(chainID *big.Int, signers ...Signer) *SignerManager {
+	byAddress := make(map[common.Address]Signer, len(signers))
+	for _, signer := range signers {
+		byAddress[signer.Address()] = signer
+	}
+	return &SignerManager{
+		signers:  byAddress,
+		txSigner: types.LatestSignerForChainID(chainID),
+		opts:     make(map[common.Address]*bind.TransactOpts),
+	}
+}
+
+// TransactOpts returns the cached bind.TransactOpts for address, building and
+// caching it the first time it's requested.
+func (m *SignerManager) TransactOpts(address common.Address) (*bind.TransactOpts, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if opts, ok := m.opts[address]; ok {
+		return opts, nil
+	}
+
+	signer, ok := m.signers[address]
+	if !ok {
+		return nil, fmt.Errorf("no signer configured for address %s", address)
+	}
+
+	opts := &bind.TransactOpts{
+		From: address,
+		Signer: func(_ common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), signTimeout)
+			defer cancel()
+
+			sig, err := signer.SignHash(ctx, m.txSigner.Hash(tx))
+			if err != nil {
+				return nil, fmt.Errorf("failed to sign tx for address %s: %w", address, err)
+			}
+			return tx.WithSignature(m.txSigner, sig)
+		},
+	}
+
+	m.opts[address] = opts
+	return opts, nil
+}
@@ -0,0 +1,213 @@
+package ethtxmanager
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// web3SignerRequestTimeout bounds how long a single Web3Signer sign request
+// is allowed to take, since http.DefaultClient has none - a hung or
+// unresponsive instance would otherwise block Add (and the nonce it already
+// reserved) indefinitely.
+const web3SignerRequestTimeout = 10 * time.Second
+
+// web3SignerSignRequest is the request body of a Web3Signer eth1 sign call.
+type web3SignerSignRequest struct {
+	Data string `json:"data"`
+}
+
+// web3SignerSignResponse is the response body of a Web3Signer eth1 sign call.
+type web3SignerSignResponse struct {
+	Signature string `json:"signature"`
+}
+
+// web3Signer signs hashes through a remote Web3Signer instance, speaking the
+// Web3Signer JSON schema (POST /api/v1/eth1/sign/{identifier}).
+type web3Signer struct {
+	address    common.Address
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewWeb3Signer creates a Signer that delegates signing for address to the
+// Web3Signer instance reachable at baseURL.
+func NewWeb3Signer(address common.Address, baseURL string) Signer {
+	return &web3Signer{
+		address:    address,
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: web3SignerRequestTimeout},
+	}
+}
+
+func (s *web3Signer) Address() common.Address {
+	return s.address
+}
+
+func (s *web3Signer) SignHash(ctx context.Context, hash [32]byte) ([]byte, error) {
+	body, err := json.Marshal(web3SignerSignRequest{Data: fmt.Sprintf("0x%x", hash)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal web3signer request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/api/v1/eth1/sign/%s", s.baseURL, s.address.Hex())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build web3signer request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call web3signer: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("web3signer returned status %d", res.StatusCode)
+	}
+
+	var signResponse web3SignerSignResponse
+	if err := json.NewDecoder(res.Body).Decode(&signResponse); err != nil {
+		return nil, fmt.Errorf("failed to decode web3signer response: %w", err)
+	}
+
+	signature := common.FromHex(signResponse.Signature)
+	if len(signature) != crypto.SignatureLength {
+		return nil, fmt.Errorf("web3signer returned a signature of unexpected length %d", len(signature))
+	}
+
+	// Web3Signer's eth1 sign endpoint returns the recovery byte using the
+	// 27/28 convention, but types.Signer.SignatureValues (and tx.WithSignature)
+	// expect 0/1.
+	if signature[64] >= 27 {
+		signature[64] -= 27
+	}
+
+	return signature, nil
+}
+
+// kmsClient is the subset of a cloud KMS API (AWS KMS, GCP KMS) needed to
+// derive an Ethereum address and sign a hash, implementations wrap the
+// respective cloud SDK clients so this package doesn't depend on them directly.
+type kmsClient interface {
+	// PublicKey returns the DER-encoded public key of the configured key.
+	PublicKey(ctx context.Context) ([]byte, error)
+	// Sign signs digest (a keccak256 hash) and returns the ASN.1 DER-encoded
+	// ECDSA signature produced by the KMS.
+	Sign(ctx context.Context, digest [32]byte) ([]byte, error)
+}
+
+// kmsSigner signs hashes through a cloud KMS asymmetric signing key (AWS KMS
+// or GCP KMS), deriving the Ethereum address from the key's public key.
+type kmsSigner struct {
+	client  kmsClient
+	address common.Address
+}
+
+// newKMSSigner resolves the Ethereum address for the KMS key served by client
+// and returns a Signer backed by it.
+func newKMSSigner(ctx context.Context, client kmsClient) (Signer, error) {
+	pubKeyDER, err := client.PublicKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch KMS public key: %w", err)
+	}
+
+	// The DER encoding is an X.509 SubjectPublicKeyInfo whose bit string holds
+	// the uncompressed secp256k1 point.
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(pubKeyDER, &spki); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal KMS public key: %w", err)
+	}
+
+	pubKey, err := crypto.UnmarshalPubkey(spki.PublicKey.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse KMS public key: %w", err)
+	}
+
+	return &kmsSigner{
+		client:  client,
+		address: crypto.PubkeyToAddress(*pubKey),
+	}, nil
+}
+
+func (s *kmsSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *kmsSigner) SignHash(ctx context.Context, hash [32]byte) ([]byte, error) {
+	derSignature, err := s.client.Sign(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign hash with KMS key for address %s: %w", s.address, err)
+	}
+
+	r, sVal, err := unmarshalDERSignature(derSignature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode KMS signature for address %s: %w", s.address, err)
+	}
+
+	return recoverableSignature(hash, r, sVal, s.address)
+}
+
+// derECDSASignature is the ASN.1 structure used by AWS KMS and GCP KMS to
+// encode the (r, s) pair of an asymmetric ECDSA sign response.
+type derECDSASignature struct {
+	R, S *big.Int
+}
+
+// unmarshalDERSignature decodes the ASN.1 DER-encoded ECDSA signature
+// returned by AWS KMS and GCP KMS asymmetric sign operations.
+func unmarshalDERSignature(der []byte) (r, s []byte, err error) {
+	var sig derECDSASignature
+	if _, err := asn1.Unmarshal(der, &sig); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal ASN.1 signature: %w", err)
+	}
+	return sig.R.Bytes(), sig.S.Bytes(), nil
+}
+
+// secp256k1HalfN is half the secp256k1 curve order, used to normalize KMS
+// signatures to the canonical low-S form Ethereum requires.
+var secp256k1HalfN = new(big.Int).Rsh(crypto.S256().Params().N, 1)
+
+// recoverableSignature brute-forces the recovery id for an (r, s) ECDSA
+// signature, since KMS backends don't return it, by trying both candidates
+// and keeping the one that recovers to address. AWS KMS and GCP KMS don't
+// guarantee a low-S signature, but go-ethereum's homestead signature
+// validation rejects any s > secp256k1n/2, so s is normalized to its
+// low-S counterpart (N - s) before the recovery id is searched.
+func recoverableSignature(hash [32]byte, r, s []byte, address common.Address) ([]byte, error) {
+	sInt := new(big.Int).SetBytes(s)
+	if sInt.Cmp(secp256k1HalfN) > 0 {
+		sInt.Sub(crypto.S256().Params().N, sInt)
+		s = sInt.Bytes()
+	}
+
+	signature := make([]byte, crypto.SignatureLength)
+	copy(signature[32-len(r):32], r)
+	copy(signature[64-len(s):64], s)
+
+	for recoveryID := byte(0); recoveryID < 2; recoveryID++ {
+		signature[64] = recoveryID
+		pubKey, err := crypto.SigToPub(hash[:], signature)
+		if err != nil {
+			continue
+		}
+		if crypto.PubkeyToAddress(*pubKey) == address {
+			return signature, nil
+		}
+	}
+
+	return nil, fmt.Errorf("could not recover a valid signature for address %s", address)
+}
@@ -0,0 +1,146 @@
+package ethtxmanager
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestRecoverableSignatureRecoversCorrectAddress(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	var hash [32]byte
+	copy(hash[:], crypto.Keccak256([]byte("recoverableSignature test")))
+
+	r, s := rawECDSASign(t, key, hash)
+
+	signature, err := recoverableSignature(hash, r, s, address)
+	if err != nil {
+		t.Fatalf("recoverableSignature returned an error: %v", err)
+	}
+
+	pubKey, err := crypto.SigToPub(hash[:], signature)
+	if err != nil {
+		t.Fatalf("failed to recover public key: %v", err)
+	}
+	if got := crypto.PubkeyToAddress(*pubKey); got != address {
+		t.Errorf("recovered address %s, want %s", got, address)
+	}
+}
+
+func TestRecoverableSignatureNormalizesHighS(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	var hash [32]byte
+	copy(hash[:], crypto.Keccak256([]byte("high-S normalization test")))
+
+	r, s := rawECDSASign(t, key, hash)
+
+	// Force a high-S signature, as AWS/GCP KMS may return, to confirm
+	// recoverableSignature normalizes it to the canonical low-S form instead
+	// of returning a signature the network would reject.
+	sInt := new(big.Int).SetBytes(s)
+	if sInt.Cmp(secp256k1HalfN) <= 0 {
+		sInt.Sub(crypto.S256().Params().N, sInt)
+		s = sInt.Bytes()
+	}
+
+	signature, err := recoverableSignature(hash, r, s, address)
+	if err != nil {
+		t.Fatalf("recoverableSignature returned an error for a high-S input: %v", err)
+	}
+
+	normalizedS := new(big.Int).SetBytes(signature[32:64])
+	if normalizedS.Cmp(secp256k1HalfN) > 0 {
+		t.Errorf("recoverableSignature returned a high-S signature: s=%s, halfN=%s", normalizedS, secp256k1HalfN)
+	}
+
+	pubKey, err := crypto.SigToPub(hash[:], signature)
+	if err != nil {
+		t.Fatalf("failed to recover public key from normalized signature: %v", err)
+	}
+	if got := crypto.PubkeyToAddress(*pubKey); got != address {
+		t.Errorf("recovered address %s, want %s", got, address)
+	}
+}
+
+func TestRecoverableSignatureWrongAddressFails(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	otherKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	wrongAddress := crypto.PubkeyToAddress(otherKey.PublicKey)
+
+	var hash [32]byte
+	copy(hash[:], crypto.Keccak256([]byte("wrong address test")))
+	r, s := rawECDSASign(t, key, hash)
+
+	if _, err := recoverableSignature(hash, r, s, wrongAddress); err == nil {
+		t.Error("expected recoverableSignature to fail recovering to an unrelated address")
+	}
+}
+
+// rawECDSASign signs hash with key and returns the raw (r, s) pair, without a
+// recovery id, mirroring the output of a KMS asymmetric sign operation.
+func rawECDSASign(t *testing.T, key *ecdsa.PrivateKey, hash [32]byte) (r, s []byte) {
+	t.Helper()
+	signature, err := crypto.Sign(hash[:], key)
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	return signature[:32], signature[32:64]
+}
+
+func TestWeb3SignerSignHashNormalizesRecoveryByte(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	var hash [32]byte
+	copy(hash[:], crypto.Keccak256([]byte("web3signer v normalization test")))
+
+	signature, err := crypto.Sign(hash[:], key)
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	// Web3Signer returns the recovery byte using the 27/28 convention.
+	web3SignerStyle := append([]byte{}, signature...)
+	web3SignerStyle[64] += 27
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(web3SignerSignResponse{Signature: "0x" + common.Bytes2Hex(web3SignerStyle)})
+	}))
+	defer server.Close()
+
+	signer := NewWeb3Signer(address, server.URL)
+	got, err := signer.SignHash(context.Background(), hash)
+	if err != nil {
+		t.Fatalf("SignHash returned an error: %v", err)
+	}
+
+	if got[64] != signature[64] {
+		t.Errorf("SignHash returned recovery byte %d, want %d (normalized from Web3Signer's 27/28 convention)", got[64], signature[64])
+	}
+}
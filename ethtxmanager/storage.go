@@ -0,0 +1,263 @@
+package ethtxmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/0xPolygonHermez/zkevm-ethtx-manager/log"
+)
+
+// persistDebounce is how long MemStorage waits after a mutation before
+// writing the current state to disk, coalescing bursts of writes (e.g.
+// confirming one tx while another carrying a multi-hundred-KB blob sidecar
+// is also pending) into a single marshal/rewrite instead of one per call.
+const persistDebounce = 200 * time.Millisecond
+
+// MonitoredTxStatus is the lifecycle state of a tx tracked by the manager.
+type MonitoredTxStatus string
+
+const (
+	// MonitoredStatusCreated is set right after a tx is built and signed, before it's broadcast.
+	MonitoredStatusCreated MonitoredTxStatus = "created"
+	// MonitoredStatusSent is set once the tx (or its latest repriced replacement) has been broadcast.
+	MonitoredStatusSent MonitoredTxStatus = "sent"
+	// MonitoredStatusMined is set once a successful receipt for the tx is observed.
+	MonitoredStatusMined MonitoredTxStatus = "mined"
+	// MonitoredStatusFailed is set once a receipt shows the tx was mined but reverted.
+	MonitoredStatusFailed MonitoredTxStatus = "failed"
+)
+
+// MonitoredTx is a tx tracked by the manager from creation until it's mined,
+// persisted so a restart can resume monitoring and repricing it.
+type MonitoredTx struct {
+	// ID identifies this logical tx to callers (the hash of the first version sent).
+	ID common.Hash
+
+	From   common.Address
+	To     *common.Address
+	Nonce  uint64
+	Value  *big.Int
+	Data   []byte
+	Gas    uint64
+	TxType TxType
+
+	GasPrice  *big.Int // used when TxType is TxTypeLegacy
+	GasTipCap *big.Int // used when TxType is TxTypeDynamic or a blob tx
+	GasFeeCap *big.Int // used when TxType is TxTypeDynamic or a blob tx
+
+	BlobFeeCap  *big.Int     // used when BlobSidecar is set
+	BlobSidecar *BlobSidecar // set for EIP-4844 blob txs, so restarts can re-broadcast until inclusion
+
+	Status  MonitoredTxStatus
+	History []common.Hash // every tx hash sent for this logical tx, most recent last
+
+	// LastSentAt is when the latest version in History was broadcast, used by
+	// the monitor loop to tell whether Config.WaitTxToBeMined has elapsed.
+	LastSentAt time.Time
+}
+
+// LatestHash returns the hash of the most recently broadcast version of tx.
+func (tx *MonitoredTx) LatestHash() common.Hash {
+	if len(tx.History) == 0 {
+		return tx.ID
+	}
+	return tx.History[len(tx.History)-1]
+}
+
+// Storage persists the txs the manager is tracking and the batch→call-IDs
+// mapping used by the aggregator, so both survive a restart.
+type Storage interface {
+	Add(ctx context.Context, tx MonitoredTx) error
+	Get(ctx context.Context, id common.Hash) (MonitoredTx, error)
+	GetByStatus(ctx context.Context, statuses []MonitoredTxStatus) ([]MonitoredTx, error)
+	Update(ctx context.Context, tx MonitoredTx) error
+	Remove(ctx context.Context, id common.Hash) error
+
+	batchStorage
+	callResultStorage
+}
+
+// callResultStorage persists the outcome of an aggregated call keyed by its
+// call ID, so a caller can retrieve its result after a restart even though
+// its original in-process result channel is gone.
+type callResultStorage interface {
+	StoreCallResult(ctx context.Context, callID string, result CallResult) error
+	GetCallResult(ctx context.Context, callID string) (CallResult, bool, error)
+}
+
+// persistedState is the on-disk representation of a MemStorage, written to
+// Config.PersistenceFilename so pending txs survive a restart.
+type persistedState struct {
+	Txs         map[common.Hash]MonitoredTx `json:"txs"`
+	Batches     map[common.Hash][]string    `json:"batches"`
+	CallResults map[string]CallResult       `json:"callResults"`
+}
+
+// MemStorage is an in-memory Storage implementation, optionally persisted as
+// JSON to Config.PersistenceFilename so a restart can pick up where it left off.
+type MemStorage struct {
+	mu           sync.Mutex
+	filename     string
+	state        persistedState
+	persistTimer *time.Timer
+}
+
+// NewMemStorage creates a MemStorage, loading any previously persisted state
+// from filename if it exists and readPending is true.
+func NewMemStorage(filename string, readPending bool) (*MemStorage, error) {
+	s := &MemStorage{
+		filename: filename,
+		state: persistedState{
+			Txs:         make(map[common.Hash]MonitoredTx),
+			Batches:     make(map[common.Hash][]string),
+			CallResults: make(map[string]CallResult),
+		},
+	}
+
+	if !readPending || filename == "" {
+		return s, nil
+	}
+
+	raw, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read persisted state from %s: %w", filename, err)
+	}
+	if err := json.Unmarshal(raw, &s.state); err != nil {
+		return nil, fmt.Errorf("failed to parse persisted state from %s: %w", filename, err)
+	}
+	return s, nil
+}
+
+// persistLocked writes the current state to s.filename, the caller must hold s.mu.
+func (s *MemStorage) persistLocked() error {
+	if s.filename == "" {
+		return nil
+	}
+	raw, err := json.Marshal(s.state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal persisted state: %w", err)
+	}
+	if err := os.WriteFile(s.filename, raw, 0600); err != nil {
+		return fmt.Errorf("failed to write persisted state to %s: %w", s.filename, err)
+	}
+	return nil
+}
+
+// markDirtyLocked schedules a write of the current state persistDebounce from
+// now, coalescing a burst of mutations into a single marshal/rewrite instead
+// of one per call - a blob sidecar can be a few hundred KB, so rewriting the
+// whole file on every unrelated tx update doesn't scale. A mutation is never
+// lost to the coalescing: persistLocked always reads whatever s.state is at
+// fire time, not a snapshot taken when the timer was scheduled. The caller
+// must hold s.mu.
+func (s *MemStorage) markDirtyLocked() {
+	if s.filename == "" {
+		return
+	}
+	if s.persistTimer != nil {
+		return
+	}
+	s.persistTimer = time.AfterFunc(persistDebounce, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.persistTimer = nil
+		if err := s.persistLocked(); err != nil {
+			log.Errorf("failed to persist ethtxmanager state: %v", err)
+		}
+	})
+}
+
+func (s *MemStorage) Add(_ context.Context, tx MonitoredTx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.Txs[tx.ID] = tx
+	s.markDirtyLocked()
+	return nil
+}
+
+func (s *MemStorage) Get(_ context.Context, id common.Hash) (MonitoredTx, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tx, ok := s.state.Txs[id]
+	if !ok {
+		return MonitoredTx{}, fmt.Errorf("tx %s not found", id)
+	}
+	return tx, nil
+}
+
+func (s *MemStorage) GetByStatus(_ context.Context, statuses []MonitoredTxStatus) ([]MonitoredTx, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wanted := make(map[MonitoredTxStatus]bool, len(statuses))
+	for _, status := range statuses {
+		wanted[status] = true
+	}
+
+	var result []MonitoredTx
+	for _, tx := range s.state.Txs {
+		if len(wanted) == 0 || wanted[tx.Status] {
+			result = append(result, tx)
+		}
+	}
+	return result, nil
+}
+
+func (s *MemStorage) Update(_ context.Context, tx MonitoredTx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.Txs[tx.ID] = tx
+	s.markDirtyLocked()
+	return nil
+}
+
+func (s *MemStorage) Remove(_ context.Context, id common.Hash) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.state.Txs, id)
+	s.markDirtyLocked()
+	return nil
+}
+
+func (s *MemStorage) StoreBatch(_ context.Context, txHash common.Hash, callIDs []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.Batches[txHash] = callIDs
+	s.markDirtyLocked()
+	return nil
+}
+
+func (s *MemStorage) GetBatch(_ context.Context, txHash common.Hash) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	callIDs, ok := s.state.Batches[txHash]
+	if !ok {
+		return nil, fmt.Errorf("no batch found for tx %s", txHash)
+	}
+	return callIDs, nil
+}
+
+func (s *MemStorage) StoreCallResult(_ context.Context, callID string, result CallResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.CallResults[callID] = result
+	s.markDirtyLocked()
+	return nil
+}
+
+func (s *MemStorage) GetCallResult(_ context.Context, callID string) (CallResult, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result, ok := s.state.CallResults[callID]
+	return result, ok, nil
+}